@@ -0,0 +1,379 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// AccessTokenType is the resource a Bitbucket access token is bound to.
+type AccessTokenType string
+
+const (
+	AccessTokenRepository AccessTokenType = "repository"
+	AccessTokenProject    AccessTokenType = "project"
+	AccessTokenWorkspace  AccessTokenType = "workspace"
+)
+
+// accessTokenDetectTimeout bounds the request made to inspect an access token
+// when the provider is configured.
+const accessTokenDetectTimeout = 30 * time.Second
+
+// accessTokenLevels orders the token types by reach: a token can manage
+// resources at its own level and below.
+var accessTokenLevels = map[AccessTokenType]int{
+	AccessTokenRepository: 1,
+	AccessTokenProject:    2,
+	AccessTokenWorkspace:  3,
+}
+
+// impliedScopes lists the scopes that Bitbucket grants implicitly along with
+// a scope.
+var impliedScopes = map[string][]string{
+	"repository:write":  {"repository"},
+	"pullrequest":       {"repository"},
+	"pullrequest:write": {"pullrequest", "repository:write"},
+	"project":           {"repository"},
+	"project:admin":     {"project"},
+	"pipeline:write":    {"pipeline"},
+	"pipeline:variable": {"pipeline"},
+	"runner:write":      {"runner"},
+	"issue":             {"repository"},
+	"issue:write":       {"issue"},
+	"snippet:write":     {"snippet"},
+	"account:write":     {"account"},
+}
+
+// AccessToken is a repository, project or workspace access token together with
+// what the provider detected about it when it was configured. Bitbucket binds
+// each access token to a single repository, project or workspace.
+type AccessToken struct {
+	Token string
+
+	// Type is empty when the token could not be inspected.
+	Type       AccessTokenType
+	Workspace  string
+	Project    string
+	Repository string
+	// Scopes is nil when Bitbucket did not report the token's scopes.
+	Scopes []string
+}
+
+// HasScope reports whether the token grants scope, directly or implied by
+// one of its scopes. It returns true when the scopes are unknown.
+func (t *AccessToken) HasScope(scope string) bool {
+	if t.Scopes == nil {
+		return true
+	}
+
+	seen := map[string]bool{}
+	pending := append([]string(nil), t.Scopes...)
+	for len(pending) > 0 {
+		s := pending[0]
+		pending = pending[1:]
+		if s == scope {
+			return true
+		}
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		pending = append(pending, impliedScopes[s]...)
+	}
+	return false
+}
+
+func (t *AccessToken) String() string {
+	switch t.Type {
+	case AccessTokenRepository:
+		return fmt.Sprintf("repository access token for %s/%s", t.Workspace, t.Repository)
+	case AccessTokenProject:
+		return fmt.Sprintf("project access token for %s/%s", t.Workspace, t.Project)
+	case AccessTokenWorkspace:
+		return fmt.Sprintf("workspace access token for %s", t.Workspace)
+	}
+	return "access token"
+}
+
+type accessTokenRepository struct {
+	FullName string `json:"full_name"`
+	Slug     string `json:"slug"`
+	Project  struct {
+		Key string `json:"key"`
+	} `json:"project"`
+	Workspace struct {
+		Slug string `json:"slug"`
+	} `json:"workspace"`
+}
+
+type accessTokenRepositoryPage struct {
+	Values []accessTokenRepository `json:"values"`
+	Next   string                  `json:"next,omitempty"`
+}
+
+// detectAccessToken inspects an access token by listing the repositories it
+// can see. The granted scopes are read from the X-OAuth-Scopes response
+// header. Bitbucket has no endpoint describing the token itself, so the type
+// is inferred: only workspace tokens can hold the `project:admin` and
+// `account` scopes and only project and workspace tokens the `project` scope;
+// otherwise a token that sees a single repository is a repository token and
+// one that sees repositories of a single project is a project token.
+func detectAccessToken(ctx context.Context, client *Client, token string) (*AccessToken, error) {
+	accessToken := &AccessToken{Token: token}
+
+	fields := "next,values.full_name,values.slug,values.project.key,values.workspace.slug"
+	resp, err := client.GetContext(ctx, "2.0/repositories?role=member&pagelen=100&fields="+fields)
+	if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("access_token was rejected by Bitbucket: %w", err)
+	}
+	if err != nil {
+		log.Printf("[WARN] Unable to inspect access token, skipping scope checks: %s", err)
+		return accessToken, nil
+	}
+	defer resp.Body.Close()
+
+	if header := resp.Header.Get("X-OAuth-Scopes"); header != "" {
+		accessToken.Scopes = []string{}
+		for _, scope := range strings.Split(header, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				accessToken.Scopes = append(accessToken.Scopes, scope)
+			}
+		}
+		sort.Strings(accessToken.Scopes)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var page accessTokenRepositoryPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		log.Printf("[WARN] Unable to inspect access token, skipping scope checks: %s", err)
+		return accessToken, nil
+	}
+
+	workspaces := map[string]bool{}
+	projects := map[string]bool{}
+	for _, repo := range page.Values {
+		workspaces[repo.Workspace.Slug] = true
+		projects[repo.Project.Key] = true
+	}
+	if len(workspaces) != 1 {
+		// A token that sees nothing, or several workspaces, is not an access
+		// token Bitbucket would issue; leave the type unknown.
+		log.Printf("[WARN] Unable to determine the access token type, skipping scope checks")
+		return accessToken, nil
+	}
+	first := page.Values[0]
+	accessToken.Workspace = first.Workspace.Slug
+
+	moreThanOnePage := page.Next != ""
+	switch {
+	case accessToken.Scopes != nil && (containsString(accessToken.Scopes, "project:admin") || containsString(accessToken.Scopes, "account")):
+		accessToken.Type = AccessTokenWorkspace
+	case len(projects) > 1:
+		accessToken.Type = AccessTokenWorkspace
+	case len(page.Values) == 1 && !moreThanOnePage && !containsString(accessToken.Scopes, "project"):
+		accessToken.Type = AccessTokenRepository
+		accessToken.Project = first.Project.Key
+		accessToken.Repository = first.Slug
+	default:
+		accessToken.Type = AccessTokenProject
+		accessToken.Project = first.Project.Key
+	}
+
+	log.Printf("[DEBUG] Using %s with scopes %v", accessToken, accessToken.Scopes)
+	return accessToken, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// accessTokenTarget identifies the workspace, project and repository a
+// resource manages. Empty fields are unknown at plan time or not applicable.
+type accessTokenTarget struct {
+	Workspace  string
+	Project    string
+	Repository string
+}
+
+// accessTokenRequirement describes what an access token needs to manage a
+// resource: the kind of token and the scopes, along with the attributes
+// naming the workspace, project and repository it belongs to.
+type accessTokenRequirement struct {
+	// Type is the least reaching token type that can manage the resource;
+	// empty means any type.
+	Type   AccessTokenType
+	Scopes []string
+
+	WorkspaceAttr  string
+	ProjectAttr    string
+	RepositoryAttr string
+	// RepositoryIDAttr names an attribute holding a "workspace/repo" ID,
+	// optionally followed by ":" and more.
+	RepositoryIDAttr string
+}
+
+// accessTokenRequirements lists, per resource, what an access token needs to
+// manage it. Resources missing from the list are not checked.
+var accessTokenRequirements = map[string]accessTokenRequirement{
+	"bitbucket_branch_restriction":          {Type: AccessTokenRepository, Scopes: []string{"repository:admin"}, WorkspaceAttr: "owner", RepositoryAttr: "repository"},
+	"bitbucket_branching_model":             {Type: AccessTokenRepository, Scopes: []string{"repository:admin"}, WorkspaceAttr: "owner", RepositoryAttr: "repository"},
+	"bitbucket_commit_file":                 {Type: AccessTokenRepository, Scopes: []string{"repository:write"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_commit_files":                {Type: AccessTokenRepository, Scopes: []string{"repository:write"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_commit_report":               {Type: AccessTokenRepository, Scopes: []string{"repository"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_commit_report_annotations":   {Type: AccessTokenRepository, Scopes: []string{"repository"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_commit_status":               {Type: AccessTokenRepository, Scopes: []string{"repository"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_default_reviewers":           {Type: AccessTokenRepository, Scopes: []string{"pullrequest", "repository:admin"}, WorkspaceAttr: "owner", RepositoryAttr: "repository"},
+	"bitbucket_deploy_key":                  {Type: AccessTokenRepository, Scopes: []string{"repository:admin"}, WorkspaceAttr: "workspace", RepositoryAttr: "repository"},
+	"bitbucket_deployment":                  {Type: AccessTokenRepository, Scopes: []string{"pipeline"}, RepositoryIDAttr: "repository"},
+	"bitbucket_deployment_variable":         {Type: AccessTokenRepository, Scopes: []string{"pipeline:variable"}, RepositoryIDAttr: "deployment"},
+	"bitbucket_deployment_variables":        {Type: AccessTokenRepository, Scopes: []string{"pipeline:variable"}, RepositoryIDAttr: "deployment"},
+	"bitbucket_forked_repository":           {Type: AccessTokenWorkspace, Scopes: []string{"repository:admin"}, WorkspaceAttr: "owner"},
+	"bitbucket_group":                       {Scopes: []string{"account:write"}},
+	"bitbucket_group_membership":            {Scopes: []string{"account:write"}},
+	"bitbucket_hook":                        {Type: AccessTokenRepository, Scopes: []string{"webhook"}, WorkspaceAttr: "owner", RepositoryAttr: "repository"},
+	"bitbucket_issue":                       {Type: AccessTokenRepository, Scopes: []string{"issue:write"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_pipeline_cache_cleanup":      {Type: AccessTokenRepository, Scopes: []string{"pipeline:write"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_pipeline_run":                {Type: AccessTokenRepository, Scopes: []string{"pipeline:write"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_pipeline_schedule":           {Type: AccessTokenRepository, Scopes: []string{"pipeline:write"}, WorkspaceAttr: "workspace", RepositoryAttr: "repository"},
+	"bitbucket_pipeline_ssh_key":            {Type: AccessTokenRepository, Scopes: []string{"pipeline:variable"}, WorkspaceAttr: "workspace", RepositoryAttr: "repository"},
+	"bitbucket_pipeline_ssh_known_host":     {Type: AccessTokenRepository, Scopes: []string{"pipeline:variable"}, WorkspaceAttr: "workspace", RepositoryAttr: "repository"},
+	"bitbucket_pipeline_stop":               {Type: AccessTokenRepository, Scopes: []string{"pipeline:write"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_project":                     {Type: AccessTokenWorkspace, Scopes: []string{"project:admin"}, WorkspaceAttr: "owner"},
+	"bitbucket_project_branching_model":     {Type: AccessTokenProject, Scopes: []string{"project:admin"}, WorkspaceAttr: "workspace", ProjectAttr: "project"},
+	"bitbucket_project_default_reviewers":   {Type: AccessTokenProject, Scopes: []string{"project:admin"}, WorkspaceAttr: "workspace", ProjectAttr: "project"},
+	"bitbucket_project_deploy_key":          {Type: AccessTokenProject, Scopes: []string{"project:admin"}, WorkspaceAttr: "workspace", ProjectAttr: "project_key"},
+	"bitbucket_project_group_permission":    {Type: AccessTokenProject, Scopes: []string{"project:admin"}, WorkspaceAttr: "workspace", ProjectAttr: "project_key"},
+	"bitbucket_project_user_permission":     {Type: AccessTokenProject, Scopes: []string{"project:admin"}, WorkspaceAttr: "workspace", ProjectAttr: "project_key"},
+	"bitbucket_pull_request":                {Type: AccessTokenRepository, Scopes: []string{"pullrequest:write"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_repository":                  {Type: AccessTokenProject, Scopes: []string{"repository:admin", "repository:delete"}, WorkspaceAttr: "owner", ProjectAttr: "project_key"},
+	"bitbucket_repository_download":         {Type: AccessTokenRepository, Scopes: []string{"repository:write"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_repository_group_permission": {Type: AccessTokenRepository, Scopes: []string{"repository:admin"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_repository_issue_export":     {Type: AccessTokenRepository, Scopes: []string{"issue"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_repository_issue_import":     {Type: AccessTokenRepository, Scopes: []string{"issue:write"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_repository_pipeline_config":  {Type: AccessTokenRepository, Scopes: []string{"repository:admin"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_repository_permissions":      {Type: AccessTokenRepository, Scopes: []string{"repository:admin"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_repository_pipeline_runner":  {Type: AccessTokenRepository, Scopes: []string{"runner:write"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_repository_user_permission":  {Type: AccessTokenRepository, Scopes: []string{"repository:admin"}, WorkspaceAttr: "workspace", RepositoryAttr: "repo_slug"},
+	"bitbucket_repository_variable":         {Type: AccessTokenRepository, Scopes: []string{"pipeline:variable"}, RepositoryIDAttr: "repository"},
+	"bitbucket_snippet":                     {Scopes: []string{"snippet:write"}},
+	"bitbucket_ssh_key":                     {Scopes: []string{"account:write"}},
+	"bitbucket_user_gpg_key":                {Scopes: []string{"account:write"}},
+	"bitbucket_workspace_hook":              {Type: AccessTokenWorkspace, Scopes: []string{"webhook"}, WorkspaceAttr: "workspace"},
+	"bitbucket_workspace_pipeline_runner":   {Type: AccessTokenWorkspace, Scopes: []string{"runner:write"}, WorkspaceAttr: "workspace"},
+	"bitbucket_workspace_variable":          {Type: AccessTokenWorkspace, Scopes: []string{"pipeline:variable"}, WorkspaceAttr: "workspace"},
+	"bitbucket_workspace_variables":         {Type: AccessTokenWorkspace, Scopes: []string{"pipeline:variable"}, WorkspaceAttr: "workspace"},
+}
+
+// check returns an error explaining why token cannot manage target.
+func (r accessTokenRequirement) check(token *AccessToken, target accessTokenTarget) error {
+	var missing []string
+	for _, scope := range r.Scopes {
+		if !token.HasScope(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the provider is authenticated with a %s, which lacks the %s scope(s) this resource requires (granted: %s)",
+			token, strings.Join(missing, ", "), strings.Join(token.Scopes, ", "))
+	}
+
+	if token.Type == "" {
+		return nil
+	}
+
+	if r.Type != "" && accessTokenLevels[token.Type] < accessTokenLevels[r.Type] {
+		return fmt.Errorf("the provider is authenticated with a %s, but this resource can only be managed with a %s access token or one with a wider reach",
+			token, r.Type)
+	}
+
+	if target.Workspace != "" && !strings.EqualFold(target.Workspace, token.Workspace) {
+		return fmt.Errorf("the provider is authenticated with a %s, which cannot access workspace %q", token, target.Workspace)
+	}
+	if token.Type == AccessTokenProject || token.Type == AccessTokenRepository {
+		if target.Project != "" && !strings.EqualFold(target.Project, token.Project) {
+			return fmt.Errorf("the provider is authenticated with a %s, which cannot access project %q", token, target.Project)
+		}
+	}
+	if token.Type == AccessTokenRepository {
+		if target.Repository != "" && !strings.EqualFold(target.Repository, token.Repository) {
+			return fmt.Errorf("the provider is authenticated with a %s, which cannot access repository %q", token, target.Repository)
+		}
+	}
+
+	return nil
+}
+
+// target reads the workspace, project and repository of the planned resource.
+// Values that are not yet known are left empty.
+func (r accessTokenRequirement) target(d *schema.ResourceDiff) accessTokenTarget {
+	get := func(attr string) string {
+		if attr == "" || !d.NewValueKnown(attr) {
+			return ""
+		}
+		v, _ := d.Get(attr).(string)
+		return v
+	}
+
+	target := accessTokenTarget{
+		Workspace:  get(r.WorkspaceAttr),
+		Project:    get(r.ProjectAttr),
+		Repository: get(r.RepositoryAttr),
+	}
+	if id := get(r.RepositoryIDAttr); id != "" {
+		id, _, _ = strings.Cut(id, ":")
+		if workspace, repo, ok := strings.Cut(id, "/"); ok {
+			target.Workspace = workspace
+			target.Repository = repo
+		}
+	}
+	return target
+}
+
+// customizeDiff fails the plan when the provider is authenticated with an
+// access token that cannot manage the resource.
+func (r accessTokenRequirement) customizeDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	clients, ok := m.(Clients)
+	if !ok || clients.httpClient.AccessToken == nil {
+		return nil
+	}
+	return r.check(clients.httpClient.AccessToken, r.target(d))
+}
+
+// addAccessTokenChecks attaches the access token plan-time checks to the
+// resources listed in accessTokenRequirements.
+func addAccessTokenChecks(resources map[string]*schema.Resource) {
+	for name, requirement := range accessTokenRequirements {
+		resource, ok := resources[name]
+		if !ok {
+			continue
+		}
+
+		check := requirement.customizeDiff
+		if existing := resource.CustomizeDiff; existing != nil {
+			resource.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+				if err := check(ctx, d, m); err != nil {
+					return err
+				}
+				return existing(ctx, d, m)
+			}
+		} else {
+			resource.CustomizeDiff = check
+		}
+	}
+}
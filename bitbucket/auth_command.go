@@ -0,0 +1,112 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// authCommandTimeout bounds a single run of the `auth_command` program.
+const authCommandTimeout = time.Minute
+
+// authCommandOutput is the JSON document the `auth_command` program prints on
+// stdout. The expiry is given either as an absolute RFC 3339 timestamp or as a
+// number of seconds from now; without either the token is used until Bitbucket
+// rejects it.
+type authCommandOutput struct {
+	AccessToken string `json:"access_token"`
+	// Token is accepted as an alias of access_token.
+	Token     string `json:"token"`
+	TokenType string `json:"token_type"`
+	ExpiresAt string `json:"expires_at"`
+	ExpiresIn int64  `json:"expires_in"`
+}
+
+// commandTokenSource is an oauth2.TokenSource that obtains tokens by running
+// an external program, such as a wrapper around a secrets manager. It is
+// wrapped in an oauth2.ReuseTokenSource, so the program only runs again once
+// the previous token is about to expire.
+type commandTokenSource struct {
+	argv    []string
+	timeout time.Duration
+	now     func() time.Time
+}
+
+func newCommandTokenSource(argv []string) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(nil, &commandTokenSource{
+		argv:    argv,
+		timeout: authCommandTimeout,
+		now:     time.Now,
+	})
+}
+
+func (s *commandTokenSource) Token() (*oauth2.Token, error) {
+	if len(s.argv) == 0 || s.argv[0] == "" {
+		return nil, fmt.Errorf("auth_command is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	log.Printf("[DEBUG] Running auth_command %s", s.argv[0])
+	cmd := exec.CommandContext(ctx, s.argv[0], s.argv[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("auth_command %s timed out after %s", s.argv[0], s.timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("auth_command %s failed: %w: %s", s.argv[0], err, msg)
+		}
+		return nil, fmt.Errorf("auth_command %s failed: %w", s.argv[0], err)
+	}
+
+	return parseAuthCommandOutput(stdout.Bytes(), s.now())
+}
+
+// parseAuthCommandOutput converts the output of the `auth_command` program
+// into a token. The output is never included in errors, as it may hold the
+// secret.
+func parseAuthCommandOutput(output []byte, now time.Time) (*oauth2.Token, error) {
+	var out authCommandOutput
+	if err := json.Unmarshal(output, &out); err != nil {
+		return nil, fmt.Errorf("auth_command output is not valid JSON: %w", err)
+	}
+
+	token := &oauth2.Token{
+		AccessToken: out.AccessToken,
+		TokenType:   out.TokenType,
+	}
+	if token.AccessToken == "" {
+		token.AccessToken = out.Token
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("auth_command output has no access_token")
+	}
+	if token.TokenType == "" {
+		token.TokenType = "Bearer"
+	}
+
+	switch {
+	case out.ExpiresAt != "":
+		expiry, err := time.Parse(time.RFC3339, out.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("auth_command output has an invalid expires_at: %w", err)
+		}
+		token.Expiry = expiry
+	case out.ExpiresIn > 0:
+		token.Expiry = now.Add(time.Duration(out.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
@@ -2,14 +2,14 @@ package bitbucket
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
-	"strconv"
-	"time"
+	"strings"
 
 	"golang.org/x/oauth2"
 )
@@ -18,27 +18,9 @@ import (
 // used to build the User-Agent header sent with every API request.
 var ProviderVersion = "dev"
 
-const (
-	// maxRetries is the number of times a request is retried when the API
-	// responds with HTTP 429 (rate limited).
-	maxRetries = 3
-	// retryBaseDelay is the base delay used for exponential backoff between
-	// retries when the API does not return a Retry-After header.
-	retryBaseDelay = time.Second
-)
-
-// Error represents a error from the bitbucket api.
-type Error struct {
-	APIError struct {
-		Message string `json:"message,omitempty"`
-	} `json:"error,omitempty"`
-	Type       string `json:"type,omitempty"`
-	StatusCode int
-	Endpoint   string
-}
-
-func (e Error) Error() string {
-	return fmt.Sprintf("API Error: %d %s %s", e.StatusCode, e.Endpoint, e.APIError.Message)
+// userAgent returns the User-Agent header sent with every API request.
+func userAgent() string {
+	return "terraform-provider-bitbucket/" + ProviderVersion
 }
 
 const (
@@ -49,94 +31,96 @@ const (
 // Client is the base internal Client to talk to bitbuckets API. This should be a username and password
 // the password should be a app-password.
 type Client struct {
+	// BaseURL is the API root that endpoints are resolved against. When empty
+	// BitbucketEndpoint is used, which lets the provider be pointed at an
+	// API-compatible proxy or a local stand-in server.
+	BaseURL          string
 	Username         *string
 	Password         *string
 	OAuthToken       *string
 	OAuthTokenSource oauth2.TokenSource
-	HTTPClient       *http.Client
+	// AccessToken is set when authenticating with a repository, project or
+	// workspace access token.
+	AccessToken *AccessToken
+	HTTPClient  *http.Client
+}
+
+// baseURL returns the API root requests are sent to, always ending in a slash
+// so that relative endpoints can be appended directly.
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return BitbucketEndpoint
+	}
+	return strings.TrimSuffix(c.BaseURL, "/") + "/"
 }
 
 // Do Will just call the bitbucket api but also add auth to it and some extra headers
 func (c *Client) Do(method, endpoint string, payload *bytes.Buffer, contentType string) (*http.Response, error) {
-	absoluteendpoint := BitbucketEndpoint + endpoint
+	return c.DoContext(context.Background(), method, endpoint, payload, contentType)
+}
+
+// DoContext is Do bound to ctx: the request, and any retry backoff, is
+// abandoned as soon as ctx is cancelled or its deadline passes.
+func (c *Client) DoContext(ctx context.Context, method, endpoint string, payload *bytes.Buffer, contentType string) (*http.Response, error) {
+	absoluteendpoint := c.baseURL() + endpoint
 	log.Printf("[DEBUG] Sending request to %s %s", method, absoluteendpoint)
 
-	// Capture the payload once so the request can be safely rebuilt on retry.
-	var body []byte
+	var bodyreader io.Reader
 	if payload != nil {
-		body = payload.Bytes()
+		// A bytes.Reader lets the retry transport replay the body.
+		body := payload.Bytes()
 		log.Printf("[DEBUG] With payload %s", string(body))
+		bodyreader = bytes.NewReader(body)
 	}
 
-	var resp *http.Response
-
-	for attempt := 0; ; attempt++ {
-		var bodyreader io.Reader
-		if payload != nil {
-			bodyreader = bytes.NewReader(body)
-		}
-
-		req, err := http.NewRequest(method, absoluteendpoint, bodyreader)
-		if err != nil {
-			return nil, err
-		}
-
-		if c.Username != nil && c.Password != nil {
-			log.Printf("[DEBUG] Setting Basic Auth")
-			req.SetBasicAuth(*c.Username, *c.Password)
-		}
+	req, err := http.NewRequestWithContext(ctx, method, absoluteendpoint, bodyreader)
+	if err != nil {
+		return nil, err
+	}
 
-		if c.OAuthToken != nil {
-			log.Printf("[DEBUG] Setting Bearer Token")
-			bearer := "Bearer " + *c.OAuthToken
-			req.Header.Add("Authorization", bearer)
-		}
+	if c.Username != nil && c.Password != nil {
+		log.Printf("[DEBUG] Setting Basic Auth")
+		req.SetBasicAuth(*c.Username, *c.Password)
+	}
 
-		if c.OAuthTokenSource != nil {
-			token, err := c.OAuthTokenSource.Token()
-			if err != nil {
-				return nil, err
-			}
+	if c.OAuthToken != nil {
+		log.Printf("[DEBUG] Setting Bearer Token")
+		bearer := "Bearer " + *c.OAuthToken
+		req.Header.Add("Authorization", bearer)
+	}
 
-			token.SetAuthHeader(req)
-		}
+	if c.AccessToken != nil {
+		log.Printf("[DEBUG] Setting Access Token")
+		req.Header.Set("Authorization", "Bearer "+c.AccessToken.Token)
+	}
 
-		if payload != nil && contentType != "" {
-			// Can cause bad request when putting default reviews if set.
-			req.Header.Add("Content-Type", contentType)
+	if c.OAuthTokenSource != nil {
+		token, err := c.OAuthTokenSource.Token()
+		if err != nil {
+			return nil, err
 		}
 
-		req.Header.Set("User-Agent", "terraform-provider-bitbucket/"+ProviderVersion)
-		req.Close = true
+		token.SetAuthHeader(req)
+	}
 
-		var doErr error
-		resp, doErr = c.HTTPClient.Do(req)
-		log.Printf("[DEBUG] Resp: %v Err: %v", resp, doErr)
-		if doErr != nil {
-			return nil, doErr
-		}
-		if resp == nil {
-			return nil, fmt.Errorf("no response received from %s %s", method, absoluteendpoint)
-		}
+	if payload != nil && contentType != "" {
+		// Can cause bad request when putting default reviews if set.
+		req.Header.Add("Content-Type", contentType)
+	}
 
-		// Retry on rate limiting, honouring Retry-After when provided.
-		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries {
-			wait := retryAfterDelay(resp, attempt)
-			log.Printf("[DEBUG] Rate limited by Bitbucket, retrying in %s (attempt %d/%d)", wait, attempt+1, maxRetries)
-			resp.Body.Close()
-			time.Sleep(wait)
-			continue
-		}
+	req.Header.Set("User-Agent", userAgent())
+	req.Close = true
 
-		break
+	resp, err := c.HTTPClient.Do(req)
+	log.Printf("[DEBUG] Resp: %v Err: %v", resp, err)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("no response received from %s %s", method, absoluteendpoint)
 	}
 
 	if resp.StatusCode >= 400 || resp.StatusCode < 200 {
-		apiError := Error{
-			StatusCode: resp.StatusCode,
-			Endpoint:   endpoint,
-		}
-
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			return nil, err
@@ -144,34 +128,19 @@ func (c *Client) Do(method, endpoint string, payload *bytes.Buffer, contentType
 
 		log.Printf("[DEBUG] Resp Body: %s", string(body))
 
-		err = json.Unmarshal(body, &apiError)
-		if err != nil {
-			apiError.APIError.Message = string(body)
-		}
-
-		return resp, error(apiError)
-
+		return resp, newAPIError(resp, method, endpoint, body)
 	}
 	return resp, nil
 }
 
-// retryAfterDelay returns how long to wait before retrying a rate-limited
-// request. It uses the Retry-After response header when present (seconds),
-// otherwise it falls back to exponential backoff.
-func retryAfterDelay(resp *http.Response, attempt int) time.Duration {
-	if resp != nil {
-		if v := resp.Header.Get("Retry-After"); v != "" {
-			if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
-				return time.Duration(secs) * time.Second
-			}
-		}
-	}
-	return retryBaseDelay * time.Duration(1<<uint(attempt))
-}
-
 // Get is just a helper method to do but with a GET verb
 func (c *Client) Get(endpoint string) (*http.Response, error) {
-	return c.Do("GET", endpoint, nil, "application/json")
+	return c.GetContext(context.Background(), endpoint)
+}
+
+// GetContext is Get bound to ctx
+func (c *Client) GetContext(ctx context.Context, endpoint string) (*http.Response, error) {
+	return c.DoContext(ctx, "GET", endpoint, nil, "application/json")
 }
 
 // GetPaginated retrieves every page of a paginated Bitbucket 2.0 collection
@@ -179,11 +148,16 @@ func (c *Client) Get(endpoint string) (*http.Response, error) {
 // entries as raw JSON messages. Bitbucket collection endpoints default to a
 // small page size (10), so callers that need the full result set must paginate.
 func (c *Client) GetPaginated(endpoint string) ([]json.RawMessage, error) {
+	return c.GetPaginatedContext(context.Background(), endpoint)
+}
+
+// GetPaginatedContext is GetPaginated bound to ctx
+func (c *Client) GetPaginatedContext(ctx context.Context, endpoint string) ([]json.RawMessage, error) {
 	var values []json.RawMessage
 	next := endpoint
 
 	for next != "" {
-		res, err := c.Get(next)
+		res, err := c.GetContext(ctx, next)
 		if err != nil {
 			return nil, err
 		}
@@ -203,7 +177,7 @@ func (c *Client) GetPaginated(endpoint string) ([]json.RawMessage, error) {
 		}
 
 		values = append(values, page.Values...)
-		next = toRelativeEndpoint(page.Next)
+		next = toRelativeEndpoint(c.baseURL(), page.Next)
 	}
 
 	return values, nil
@@ -215,7 +189,12 @@ func (c *Client) GetPaginated(endpoint string) ([]json.RawMessage, error) {
 // that unmarshal a `{ "values": [...] }` response transparently receive the
 // full result set instead of only the first page.
 func (c *Client) GetAll(endpoint string) (*http.Response, error) {
-	values, err := c.GetPaginated(endpoint)
+	return c.GetAllContext(context.Background(), endpoint)
+}
+
+// GetAllContext is GetAll bound to ctx
+func (c *Client) GetAllContext(ctx context.Context, endpoint string) (*http.Response, error) {
+	values, err := c.GetPaginatedContext(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -235,9 +214,12 @@ func (c *Client) GetAll(endpoint string) (*http.Response, error) {
 }
 
 // toRelativeEndpoint converts an absolute Bitbucket API URL (as returned in the
-// `next` field of paginated responses) into an endpoint relative to
-// BitbucketEndpoint, which is what Client.Do expects.
-func toRelativeEndpoint(raw string) string {
+// `next` field of paginated responses) into an endpoint relative to base, which
+// is what Client.Do expects. The host of the link is ignored, so links pointing
+// at api.bitbucket.org and links rewritten by a proxy both resolve; when base
+// carries a path prefix (e.g. "http://localhost:8080/bitbucket/") it is
+// stripped from the link as well.
+func toRelativeEndpoint(base, raw string) string {
 	if raw == "" {
 		return ""
 	}
@@ -248,6 +230,12 @@ func toRelativeEndpoint(raw string) string {
 	}
 
 	rel := parsed.Path
+	if baseURL, err := url.Parse(base); err == nil {
+		prefix := strings.TrimSuffix(baseURL.Path, "/")
+		if prefix != "" && strings.HasPrefix(rel, prefix+"/") {
+			rel = strings.TrimPrefix(rel, prefix)
+		}
+	}
 	for len(rel) > 0 && rel[0] == '/' {
 		rel = rel[1:]
 	}
@@ -276,30 +264,60 @@ func encodeQueryParams(params map[string]string) string {
 
 // Post is just a helper method to do but with a POST verb
 func (c *Client) Post(endpoint string, jsonpayload *bytes.Buffer) (*http.Response, error) {
-	return c.Do("POST", endpoint, jsonpayload, "application/json")
+	return c.PostContext(context.Background(), endpoint, jsonpayload)
+}
+
+// PostContext is Post bound to ctx
+func (c *Client) PostContext(ctx context.Context, endpoint string, jsonpayload *bytes.Buffer) (*http.Response, error) {
+	return c.DoContext(ctx, "POST", endpoint, jsonpayload, "application/json")
 }
 
 // PostNonJson is just a helper method to do but with a POST verb without Json Header
 func (c *Client) PostNonJson(endpoint string, payload *bytes.Buffer) (*http.Response, error) {
-	return c.Do("POST", endpoint, payload, "")
+	return c.PostNonJsonContext(context.Background(), endpoint, payload)
+}
+
+// PostNonJsonContext is PostNonJson bound to ctx
+func (c *Client) PostNonJsonContext(ctx context.Context, endpoint string, payload *bytes.Buffer) (*http.Response, error) {
+	return c.DoContext(ctx, "POST", endpoint, payload, "")
 }
 
 // PostWithContentType is just a helper method to do but with a POST verb and a provided content type
 func (c *Client) PostWithContentType(endpoint, contentType string, payload *bytes.Buffer) (*http.Response, error) {
-	return c.Do("POST", endpoint, payload, contentType)
+	return c.PostWithContentTypeContext(context.Background(), endpoint, contentType, payload)
+}
+
+// PostWithContentTypeContext is PostWithContentType bound to ctx
+func (c *Client) PostWithContentTypeContext(ctx context.Context, endpoint, contentType string, payload *bytes.Buffer) (*http.Response, error) {
+	return c.DoContext(ctx, "POST", endpoint, payload, contentType)
 }
 
 // Put is just a helper method to do but with a PUT verb
 func (c *Client) Put(endpoint string, jsonpayload *bytes.Buffer) (*http.Response, error) {
-	return c.Do("PUT", endpoint, jsonpayload, "application/json")
+	return c.PutContext(context.Background(), endpoint, jsonpayload)
+}
+
+// PutContext is Put bound to ctx
+func (c *Client) PutContext(ctx context.Context, endpoint string, jsonpayload *bytes.Buffer) (*http.Response, error) {
+	return c.DoContext(ctx, "PUT", endpoint, jsonpayload, "application/json")
 }
 
 // PutOnly is just a helper method to do but with a PUT verb and a nil body
 func (c *Client) PutOnly(endpoint string) (*http.Response, error) {
-	return c.Do("PUT", endpoint, nil, "application/json")
+	return c.PutOnlyContext(context.Background(), endpoint)
+}
+
+// PutOnlyContext is PutOnly bound to ctx
+func (c *Client) PutOnlyContext(ctx context.Context, endpoint string) (*http.Response, error) {
+	return c.DoContext(ctx, "PUT", endpoint, nil, "application/json")
 }
 
 // Delete is just a helper to Do but with a DELETE verb
 func (c *Client) Delete(endpoint string) (*http.Response, error) {
-	return c.Do("DELETE", endpoint, nil, "application/json")
+	return c.DeleteContext(context.Background(), endpoint)
+}
+
+// DeleteContext is Delete bound to ctx
+func (c *Client) DeleteContext(ctx context.Context, endpoint string) (*http.Response, error) {
+	return c.DoContext(ctx, "DELETE", endpoint, nil, "application/json")
 }
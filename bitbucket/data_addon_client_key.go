@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -42,7 +41,11 @@ func dataAddonClientKeyRead(ctx context.Context, d *schema.ResourceData, m inter
 	url := fmt.Sprintf("2.0/addon/%s/client-key", addonKey)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate addon %s client key", addonKey)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -50,10 +53,6 @@ func dataAddonClientKeyRead(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.Errorf("no response returned from addon client key call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate addon %s client key", addonKey)
-	}
-
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -86,7 +85,11 @@ func dataAddonsRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	url := fmt.Sprintf("2.0/repositories/%s/%s/addon", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -94,10 +97,6 @@ func dataAddonsRead(ctx context.Context, d *schema.ResourceData, m interface{})
 		return diag.Errorf("no response returned from addons call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading addons with params (%s): ", dumpResourceData(d, dataAddons().Schema))
 	}
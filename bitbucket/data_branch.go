@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/DrFaust92/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -84,7 +83,11 @@ func dataBranchRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate branch %s in repository %s/%s", branchName, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -92,10 +95,6 @@ func dataBranchRead(ctx context.Context, d *schema.ResourceData, m interface{})
 		return diag.Errorf("no response returned from branch call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate branch %s in repository %s/%s", branchName, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading branch information with params (%s): ", dumpResourceData(d, dataBranch().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -101,7 +100,11 @@ func dataBranchMergeBaseRead(ctx context.Context, d *schema.ResourceData, m inte
 	url := fmt.Sprintf("2.0/repositories/%s/%s/merge-base/%s", workspace, repoSlug, revspec)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate a common ancestor for %s and %s in repository %s/%s", source, target, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -109,10 +112,6 @@ func dataBranchMergeBaseRead(ctx context.Context, d *schema.ResourceData, m inte
 		return diag.Errorf("no response returned from merge base call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate a common ancestor for %s and %s in repository %s/%s", source, target, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading merge base with params (%s): ", dumpResourceData(d, dataBranchMergeBase().Schema))
 	}
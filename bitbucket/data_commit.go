@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/DrFaust92/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -96,7 +95,11 @@ func dataCommitRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate commit %s in repository %s/%s", commitSha, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -104,10 +107,6 @@ func dataCommitRead(ctx context.Context, d *schema.ResourceData, m interface{})
 		return diag.Errorf("no response returned from commit call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate commit %s in repository %s/%s", commitSha, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading commit information with params (%s): ", dumpResourceData(d, dataCommit().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -80,7 +79,11 @@ func dataCommitApprovalsRead(ctx context.Context, d *schema.ResourceData, m inte
 	url := fmt.Sprintf("2.0/repositories/%s/%s/commits/%s/approvals", workspace, repoSlug, commit)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate commit %s in repository %s/%s", commit, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -88,10 +91,6 @@ func dataCommitApprovalsRead(ctx context.Context, d *schema.ResourceData, m inte
 		return diag.Errorf("no response returned from commit approvals call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate commit %s in repository %s/%s", commit, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading commit approvals with params (%s): ", dumpResourceData(d, dataCommitApprovals().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -83,7 +82,11 @@ func dataCommitCommentsRead(ctx context.Context, d *schema.ResourceData, m inter
 	url := fmt.Sprintf("2.0/repositories/%s/%s/commits/%s/comments", workspace, repoSlug, commit)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate commit %s in repository %s/%s", commit, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -91,10 +94,6 @@ func dataCommitCommentsRead(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.Errorf("no response returned from commit comments call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate commit %s in repository %s/%s", commit, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading commit comments with params (%s): ", dumpResourceData(d, dataCommitComments().Schema))
 	}
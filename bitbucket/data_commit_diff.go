@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -114,7 +113,11 @@ func dataCommitDiffRead(ctx context.Context, d *schema.ResourceData, m interface
 	url := fmt.Sprintf("2.0/repositories/%s/%s/commits/%s/diff", workspace, repoSlug, commit)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate commit %s in repository %s/%s", commit, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -122,10 +125,6 @@ func dataCommitDiffRead(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.Errorf("no response returned from commit diff call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate commit %s in repository %s/%s", commit, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading commit diff with params (%s): ", dumpResourceData(d, dataCommitDiff().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -101,7 +100,11 @@ func dataCommitReportsRead(ctx context.Context, d *schema.ResourceData, m interf
 	url := fmt.Sprintf("2.0/repositories/%s/%s/commits/%s/reports", workspace, repoSlug, commit)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate commit %s in repository %s/%s", commit, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -109,10 +112,6 @@ func dataCommitReportsRead(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.Errorf("no response returned from commit reports call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate commit %s in repository %s/%s", commit, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading commit reports with params (%s): ", dumpResourceData(d, dataCommitReports().Schema))
 	}
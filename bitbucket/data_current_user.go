@@ -69,14 +69,14 @@ func dataReadCurrentUser(ctx context.Context, d *schema.ResourceData, m interfac
 	httpClient := m.(Clients).httpClient
 	usersApi := c.ApiClient.UsersApi
 
-	curUser, res, err := usersApi.UserGet(c.AuthContext)
+	curUser, res, err := usersApi.UserGet(c.requestContext(ctx))
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] Current User: %#v", curUser)
 
-	curUserEmails, err := httpClient.Get("2.0/user/emails")
+	curUserEmails, err := httpClient.GetContext(ctx, "2.0/user/emails")
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -47,17 +47,17 @@ func dataReadDeployment(ctx context.Context, d *schema.ResourceData, m interface
 	workspace := d.Get("workspace").(string)
 	repoId := d.Get("repository").(string)
 
-	res, err := c.Get(fmt.Sprintf("2.0/repositories/%s/%s/environments/%s",
+	res, err := c.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/environments/%s",
 		workspace,
 		repoId,
 		d.Get("uuid").(string),
 	))
-	if err != nil {
-		return diag.FromErr(err)
+	if IsNotFound(err) {
+		return diag.Errorf("deployment not found")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("deployment not found")
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	if res.StatusCode >= http.StatusInternalServerError {
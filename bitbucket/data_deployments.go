@@ -43,7 +43,7 @@ func dataReadDeployments(ctx context.Context, d *schema.ResourceData, m interfac
 	workspace := d.Get("workspace").(string)
 	repoId := d.Get("repository").(string)
 
-	deploymentsResp, res, err := deployApi.GetEnvironmentsForRepository(c.AuthContext, workspace, repoId, nil)
+	deploymentsResp, res, err := deployApi.GetEnvironmentsForRepository(c.requestContext(ctx), workspace, repoId, nil)
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
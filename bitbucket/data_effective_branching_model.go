@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -78,7 +77,11 @@ func dataEffectiveBranchingModelRead(ctx context.Context, d *schema.ResourceData
 	url := fmt.Sprintf("2.0/repositories/%s/%s/effective-branching-model", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -86,10 +89,6 @@ func dataEffectiveBranchingModelRead(ctx context.Context, d *schema.ResourceData
 		return diag.Errorf("no response returned from effective branching model call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading effective branching model with params (%s): ", dumpResourceData(d, dataEffectiveBranchingModel().Schema))
 	}
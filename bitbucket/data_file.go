@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"strings"
 
 	"github.com/antihax/optional"
@@ -290,7 +289,11 @@ func dataFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) di
 		url += "?format=meta"
 	}
 
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate file with params (%s): ", dumpResourceData(d, dataFile().Schema))
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -299,10 +302,6 @@ func dataFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) di
 		return diag.Errorf("no response returned from repositories src commit call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate file with params (%s): ", dumpResourceData(d, dataFile().Schema))
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading file information with params (%s): ", dumpResourceData(d, dataFile().Schema))
 	}
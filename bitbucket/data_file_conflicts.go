@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -50,7 +49,11 @@ func dataFileConflictsRead(ctx context.Context, d *schema.ResourceData, m interf
 	url := fmt.Sprintf("2.0/repositories/%s/%s/file-conflicts/%s", workspace, repoSlug, spec)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s or spec %s", workspace, repoSlug, spec)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -58,10 +61,6 @@ func dataFileConflictsRead(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.Errorf("no response returned from file conflicts call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s or spec %s", workspace, repoSlug, spec)
-	}
-
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
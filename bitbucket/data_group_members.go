@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -88,7 +87,11 @@ func dataGroupMembersRead(ctx context.Context, d *schema.ResourceData, m interfa
 	url := fmt.Sprintf("2.0/workspaces/%s/groups/%s/members", workspace, groupSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate group %s in workspace %s", groupSlug, workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -96,10 +99,6 @@ func dataGroupMembersRead(ctx context.Context, d *schema.ResourceData, m interfa
 		return diag.Errorf("no response returned from group members call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate group %s in workspace %s", groupSlug, workspace)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading group members with params (%s): ", dumpResourceData(d, dataGroupMembers().Schema))
 	}
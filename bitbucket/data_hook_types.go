@@ -51,7 +51,7 @@ func dataReadHookTypes(ctx context.Context, d *schema.ResourceData, m interface{
 	webhooksApi := c.ApiClient.WebhooksApi
 
 	subjectType := d.Get("subject_type").(string)
-	hookTypes, res, err := webhooksApi.HookEventsSubjectTypeGet(c.AuthContext, subjectType, nil)
+	hookTypes, res, err := webhooksApi.HookEventsSubjectTypeGet(c.requestContext(ctx), subjectType, nil)
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
@@ -82,15 +82,20 @@ func dataIPRanges() *schema.Resource {
 
 func dataReadIPRanges(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	req, err := http.Get("https://ip-ranges.atlassian.com/")
+	ipRangesReq, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://ip-ranges.atlassian.com/", nil)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	if req.StatusCode == http.StatusNotFound {
+	req, err := http.DefaultClient.Do(ipRangesReq)
+	if IsNotFound(err) {
 		return diag.Errorf("IP whitelist not found")
 	}
 
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	body, readerr := io.ReadAll(req.Body)
 	if readerr != nil {
 		return diag.FromErr(readerr)
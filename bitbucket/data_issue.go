@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -98,7 +97,11 @@ func dataIssueRead(ctx context.Context, d *schema.ResourceData, m interface{}) d
 	url := fmt.Sprintf("2.0/repositories/%s/%s/issues/%s", workspace, repoSlug, issueID)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate issue %s in repository %s/%s", issueID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -106,10 +109,6 @@ func dataIssueRead(ctx context.Context, d *schema.ResourceData, m interface{}) d
 		return diag.Errorf("no response returned from issue call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate issue %s in repository %s/%s", issueID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading issue with params (%s): ", dumpResourceData(d, dataIssue().Schema))
 	}
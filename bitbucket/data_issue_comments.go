@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -83,7 +82,11 @@ func dataIssueCommentsRead(ctx context.Context, d *schema.ResourceData, m interf
 	url := fmt.Sprintf("2.0/repositories/%s/%s/issues/%s/comments", workspace, repoSlug, issueID)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate issue %s in repository %s/%s", issueID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -91,10 +94,6 @@ func dataIssueCommentsRead(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.Errorf("no response returned from issue comments call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate issue %s in repository %s/%s", issueID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading issue comments with params (%s): ", dumpResourceData(d, dataIssueComments().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -101,7 +100,11 @@ func dataIssuesRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	url := fmt.Sprintf("2.0/repositories/%s/%s/issues", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -109,10 +112,6 @@ func dataIssuesRead(ctx context.Context, d *schema.ResourceData, m interface{})
 		return diag.Errorf("no response returned from issues call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading issues with params (%s): ", dumpResourceData(d, dataIssues().Schema))
 	}
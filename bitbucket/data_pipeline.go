@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/DrFaust92/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -120,7 +119,11 @@ func dataPipelineRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate pipeline %s in repository %s/%s", pipelineNumber, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -128,10 +131,6 @@ func dataPipelineRead(ctx context.Context, d *schema.ResourceData, m interface{}
 		return diag.Errorf("no response returned from pipeline call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate pipeline %s in repository %s/%s", pipelineNumber, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pipeline information with params (%s): ", dumpResourceData(d, dataPipeline().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -46,7 +45,11 @@ func dataPipelineBuildNumberRead(ctx context.Context, d *schema.ResourceData, m
 
 	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/pipelines_config/build_number", workspace, repoSlug)
 
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s or pipeline build number", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -55,10 +58,6 @@ func dataPipelineBuildNumberRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.Errorf("no response returned from pipeline build number call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s or pipeline build number", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pipeline build number: empty response body")
 	}
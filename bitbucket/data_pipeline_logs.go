@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -70,7 +69,11 @@ func dataPipelineLogsRead(ctx context.Context, d *schema.ResourceData, m interfa
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines/%s/logs", workspace, repoSlug, pipelineUUID)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate pipeline %s in repository %s", pipelineUUID, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -78,10 +81,6 @@ func dataPipelineLogsRead(ctx context.Context, d *schema.ResourceData, m interfa
 		return diag.Errorf("no response returned from pipeline logs call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate pipeline %s in repository %s", pipelineUUID, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pipeline logs with params (%s): ", dumpResourceData(d, dataPipelineLogs().Schema))
 	}
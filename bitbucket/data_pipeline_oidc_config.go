@@ -32,13 +32,13 @@ func dataReadPipelineOidcConfig(ctx context.Context, d *schema.ResourceData, m i
 	c := m.(Clients).httpClient
 
 	workspace := d.Get("workspace").(string)
-	req, err := c.Get(fmt.Sprintf("2.0/workspaces/%s/pipelines-config/identity/oidc/.well-known/openid-configuration", workspace))
-	if err != nil {
-		return diag.FromErr(err)
+	req, err := c.GetContext(ctx, fmt.Sprintf("2.0/workspaces/%s/pipelines-config/identity/oidc/.well-known/openid-configuration", workspace))
+	if IsNotFound(err) {
+		return diag.Errorf("user not found")
 	}
 
-	if req.StatusCode == http.StatusNotFound {
-		return diag.Errorf("user not found")
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	if req.StatusCode >= http.StatusInternalServerError {
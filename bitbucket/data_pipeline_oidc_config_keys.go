@@ -33,13 +33,13 @@ func dataReadPipelineOidcConfigKeys(ctx context.Context, d *schema.ResourceData,
 	c := m.(Clients).httpClient
 
 	workspace := d.Get("workspace").(string)
-	req, err := c.Get(fmt.Sprintf("2.0/workspaces/%s/pipelines-config/identity/oidc/keys.json", workspace))
-	if err != nil {
-		return diag.FromErr(err)
+	req, err := c.GetContext(ctx, fmt.Sprintf("2.0/workspaces/%s/pipelines-config/identity/oidc/keys.json", workspace))
+	if IsNotFound(err) {
+		return diag.Errorf("user not found")
 	}
 
-	if req.StatusCode == http.StatusNotFound {
-		return diag.Errorf("user not found")
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	if req.StatusCode >= http.StatusInternalServerError {
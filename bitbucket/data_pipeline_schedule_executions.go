@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -91,7 +90,11 @@ func dataPipelineScheduleExecutionsRead(ctx context.Context, d *schema.ResourceD
 
 	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/pipelines_config/schedules/%s/executions", workspace, repoSlug, scheduleUUID)
 
-	res, err := client.GetAll(endpoint)
+	res, err := client.GetAllContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s or schedule %s", workspace, repoSlug, scheduleUUID)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -100,10 +103,6 @@ func dataPipelineScheduleExecutionsRead(ctx context.Context, d *schema.ResourceD
 		return diag.Errorf("no response returned from pipeline schedule executions call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s or schedule %s", workspace, repoSlug, scheduleUUID)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pipeline schedule executions: empty response body")
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -96,7 +95,11 @@ func dataPipelineStepsRead(ctx context.Context, d *schema.ResourceData, m interf
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines/%s/steps", workspace, repoSlug, pipelineUUID)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate pipeline %s in repository %s/%s", pipelineUUID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -104,10 +107,6 @@ func dataPipelineStepsRead(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.Errorf("no response returned from pipeline steps call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate pipeline %s in repository %s/%s", pipelineUUID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pipeline steps with params (%s): ", dumpResourceData(d, dataPipelineSteps().Schema))
 	}
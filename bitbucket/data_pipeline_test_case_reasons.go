@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -90,7 +89,11 @@ func dataPipelineTestCaseReasonsRead(ctx context.Context, d *schema.ResourceData
 
 	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/pipelines/%s/steps/%s/test_reports/test_cases/%s/test_case_reasons", workspace, repoSlug, pipelineUUID, stepUUID, testCaseUUID)
 
-	res, err := client.GetAll(endpoint)
+	res, err := client.GetAllContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s, pipeline %s, step %s, or test case %s", workspace, repoSlug, pipelineUUID, stepUUID, testCaseUUID)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -99,10 +102,6 @@ func dataPipelineTestCaseReasonsRead(ctx context.Context, d *schema.ResourceData
 		return diag.Errorf("no response returned from pipeline test case reasons call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s, pipeline %s, step %s, or test case %s", workspace, repoSlug, pipelineUUID, stepUUID, testCaseUUID)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pipeline test case reasons: empty response body")
 	}
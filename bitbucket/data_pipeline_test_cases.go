@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -98,7 +97,11 @@ func dataPipelineTestCasesRead(ctx context.Context, d *schema.ResourceData, m in
 
 	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/pipelines/%s/steps/%s/test_reports/test_cases", workspace, repoSlug, pipelineUUID, stepUUID)
 
-	res, err := client.GetAll(endpoint)
+	res, err := client.GetAllContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s, pipeline %s, or step %s", workspace, repoSlug, pipelineUUID, stepUUID)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -107,10 +110,6 @@ func dataPipelineTestCasesRead(ctx context.Context, d *schema.ResourceData, m in
 		return diag.Errorf("no response returned from pipeline test cases call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s, pipeline %s, or step %s", workspace, repoSlug, pipelineUUID, stepUUID)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pipeline test cases: empty response body")
 	}
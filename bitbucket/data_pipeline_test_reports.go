@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -74,7 +73,11 @@ func dataPipelineTestReportsRead(ctx context.Context, d *schema.ResourceData, m
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines/%s/test-reports", workspace, repoSlug, pipelineUUID)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate pipeline %s in repository %s", pipelineUUID, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -82,10 +85,6 @@ func dataPipelineTestReportsRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.Errorf("no response returned from pipeline test reports call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate pipeline %s in repository %s", pipelineUUID, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pipeline test reports with params (%s): ", dumpResourceData(d, dataPipelineTestReports().Schema))
 	}
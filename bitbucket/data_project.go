@@ -148,7 +148,7 @@ func dataReadProject(ctx context.Context, d *schema.ResourceData, m interface{})
 	)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
 	if err != nil {
 		return diag.FromErr(err)
 	}
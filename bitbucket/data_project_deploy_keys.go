@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"net/url"
 	"strings"
 
@@ -74,13 +73,13 @@ func dataProjectDeployKeysRead(ctx context.Context, d *schema.ResourceData, m in
 
 	var deployKeys []interface{}
 	for endpoint != "" {
-		res, err := client.Get(endpoint)
-		if err != nil {
-			return diag.FromErr(err)
+		res, err := client.GetContext(ctx, endpoint)
+		if IsNotFound(err) {
+			return diag.Errorf("unable to locate project %s in workspace %s", projectKey, workspace)
 		}
 
-		if res.StatusCode == http.StatusNotFound {
-			return diag.Errorf("unable to locate project %s in workspace %s", projectKey, workspace)
+		if err != nil {
+			return diag.FromErr(err)
 		}
 
 		if err := handleClientError(res, err); err != nil {
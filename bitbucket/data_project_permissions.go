@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -78,7 +77,11 @@ func dataProjectPermissionsRead(ctx context.Context, d *schema.ResourceData, m i
 	url := fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions", workspace, projectKey)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate project %s in workspace %s", projectKey, workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -86,10 +89,6 @@ func dataProjectPermissionsRead(ctx context.Context, d *schema.ResourceData, m i
 		return diag.Errorf("no response returned from project permissions call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate project %s in workspace %s", projectKey, workspace)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading project permissions with params (%s): ", dumpResourceData(d, dataProjectPermissions().Schema))
 	}
@@ -89,7 +89,7 @@ func dataProjectsRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	url := fmt.Sprintf("2.0/workspaces/%s/projects", workspace) + encodeQueryParams(params)
 
 	client := m.(Clients).httpClient
-	rawValues, err := client.GetPaginated(url)
+	rawValues, err := client.GetPaginatedContext(ctx, url)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/DrFaust92/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -136,7 +135,11 @@ func dataPullRequestRead(ctx context.Context, d *schema.ResourceData, m interfac
 	)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate pull request %s in repository %s/%s", pullRequestID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -144,10 +147,6 @@ func dataPullRequestRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.Errorf("no response returned from pull request call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate pull request %s in repository %s/%s", pullRequestID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pull request information with params (%s): ", dumpResourceData(d, dataPullRequest().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -49,7 +48,11 @@ func dataPullRequestConflictsRead(ctx context.Context, d *schema.ResourceData, m
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s/conflicts", workspace, repoSlug, pullRequestID)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate pull request %s in repository %s/%s", pullRequestID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -57,10 +60,6 @@ func dataPullRequestConflictsRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.Errorf("no response returned from pull request conflicts call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate pull request %s in repository %s/%s", pullRequestID, workspace, repoSlug)
-	}
-
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
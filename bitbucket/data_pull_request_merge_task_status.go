@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -70,7 +69,11 @@ func dataPullRequestMergeTaskStatusRead(ctx context.Context, d *schema.ResourceD
 
 	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s/merge/task-status/%s", workspace, repoSlug, pullRequestID, taskID)
 
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s, pull request %s, or merge task %s", workspace, repoSlug, pullRequestID, taskID)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -79,10 +82,6 @@ func dataPullRequestMergeTaskStatusRead(ctx context.Context, d *schema.ResourceD
 		return diag.Errorf("no response returned from pull request merge task status call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s, pull request %s, or merge task %s", workspace, repoSlug, pullRequestID, taskID)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pull request merge task status: empty response body")
 	}
@@ -100,18 +99,25 @@ func dataPullRequestMergeTaskStatusRead(ctx context.Context, d *schema.ResourceD
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s/%s/%s", workspace, repoSlug, pullRequestID, taskID))
-	d.Set("status", taskStatus.Status)
+	status := taskStatus.TaskStatus
+	if status == "" {
+		status = taskStatus.Status
+	}
+	d.Set("status", status)
 	d.Set("created_on", taskStatus.CreatedOn)
 	d.Set("updated_on", taskStatus.UpdatedOn)
 
-	log.Printf("[DEBUG] Retrieved merge task status: %s for pull request %s in repository %s/%s", taskStatus.Status, pullRequestID, workspace, repoSlug)
+	log.Printf("[DEBUG] Retrieved merge task status: %s for pull request %s in repository %s/%s", status, pullRequestID, workspace, repoSlug)
 
 	return nil
 }
 
 // MergeTaskStatus represents a merge task status
 type MergeTaskStatus struct {
-	Status    string `json:"status"`
-	CreatedOn string `json:"created_on"`
-	UpdatedOn string `json:"updated_on"`
+	// TaskStatus is PENDING while the merge runs and SUCCESS once it is done.
+	TaskStatus  string       `json:"task_status"`
+	MergeResult *PullRequest `json:"merge_result,omitempty"`
+	Status      string       `json:"status"`
+	CreatedOn   string       `json:"created_on"`
+	UpdatedOn   string       `json:"updated_on"`
 }
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -101,7 +100,11 @@ func dataPullRequestTaskRead(ctx context.Context, d *schema.ResourceData, m inte
 
 	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s/tasks/%s", workspace, repoSlug, pullRequestID, taskID)
 
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s, pull request %s, or task %s", workspace, repoSlug, pullRequestID, taskID)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -110,10 +113,6 @@ func dataPullRequestTaskRead(ctx context.Context, d *schema.ResourceData, m inte
 		return diag.Errorf("no response returned from pull request task call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s, pull request %s, or task %s", workspace, repoSlug, pullRequestID, taskID)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pull request task: empty response body")
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -102,7 +101,11 @@ func dataPullRequestTasksRead(ctx context.Context, d *schema.ResourceData, m int
 
 	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s/tasks", workspace, repoSlug, pullRequestID)
 
-	res, err := client.GetAll(endpoint)
+	res, err := client.GetAllContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s or pull request %s", workspace, repoSlug, pullRequestID)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -111,10 +114,6 @@ func dataPullRequestTasksRead(ctx context.Context, d *schema.ResourceData, m int
 		return diag.Errorf("no response returned from pull request tasks call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s or pull request %s", workspace, repoSlug, pullRequestID)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading pull request tasks: empty response body")
 	}
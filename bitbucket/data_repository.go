@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/DrFaust92/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -254,7 +253,11 @@ func dataReadRepository(ctx context.Context, d *schema.ResourceData, m interface
 	)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository with slug/UUID %s in workspace %s", repoSlug, workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -262,10 +265,6 @@ func dataReadRepository(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.Errorf("no response returned from repositories call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository with slug/UUID %s in workspace %s", repoSlug, workspace)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repo information with params (%s): ", dumpResourceData(d, dataRepository().Schema))
 	}
@@ -309,13 +308,13 @@ func fetchLatestCommitHash(ctx context.Context, client Client, workspace, repoSl
 	// Fetch the latest commit from the main branch
 	url := fmt.Sprintf("2.0/repositories/%s/%s/commits/%s", workspace, repoSlug, mainBranch)
 
-	res, err := client.Get(url)
-	if err != nil {
-		return fmt.Errorf("failed to fetch latest commit: %w", err)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return fmt.Errorf("main branch %s not found", mainBranch)
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("main branch %s not found", mainBranch)
+	if err != nil {
+		return fmt.Errorf("failed to fetch latest commit: %w", err)
 	}
 
 	if res.Body == nil {
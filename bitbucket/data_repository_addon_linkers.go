@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -86,7 +85,11 @@ func dataRepositoryAddonLinkersRead(ctx context.Context, d *schema.ResourceData,
 	url := fmt.Sprintf("2.0/repositories/%s/%s/addons", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -94,10 +97,6 @@ func dataRepositoryAddonLinkersRead(ctx context.Context, d *schema.ResourceData,
 		return diag.Errorf("no response returned from repository addon linkers call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository addon linkers with params (%s): ", dumpResourceData(d, dataRepositoryAddonLinkers().Schema))
 	}
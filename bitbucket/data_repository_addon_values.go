@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -49,7 +48,11 @@ func dataRepositoryAddonValuesRead(ctx context.Context, d *schema.ResourceData,
 	url := fmt.Sprintf("2.0/repositories/%s/%s/addons/%s", workspace, repoSlug, addonKey)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate addon %s for repository %s/%s", addonKey, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -57,10 +60,6 @@ func dataRepositoryAddonValuesRead(ctx context.Context, d *schema.ResourceData,
 		return diag.Errorf("no response returned from repository addon values call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate addon %s for repository %s/%s", addonKey, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository addon values with params (%s): ", dumpResourceData(d, dataRepositoryAddonValues().Schema))
 	}
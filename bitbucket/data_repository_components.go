@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -79,7 +78,11 @@ func dataRepositoryComponentsRead(ctx context.Context, d *schema.ResourceData, m
 	url := fmt.Sprintf("2.0/repositories/%s/%s/components", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -87,10 +90,6 @@ func dataRepositoryComponentsRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.Errorf("no response returned from repository components call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository components with params (%s): ", dumpResourceData(d, dataRepositoryComponents().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -88,7 +87,11 @@ func dataRepositoryDefaultReviewersRead(ctx context.Context, d *schema.ResourceD
 	url := fmt.Sprintf("2.0/repositories/%s/%s/default-reviewers", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -96,10 +99,6 @@ func dataRepositoryDefaultReviewersRead(ctx context.Context, d *schema.ResourceD
 		return diag.Errorf("no response returned from repository default reviewers call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository default reviewers with params (%s): ", dumpResourceData(d, dataRepositoryDefaultReviewers().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -76,7 +75,11 @@ func dataRepositoryDeployKeysRead(ctx context.Context, d *schema.ResourceData, m
 	url := fmt.Sprintf("2.0/repositories/%s/%s/deploy-keys", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -84,10 +87,6 @@ func dataRepositoryDeployKeysRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.Errorf("no response returned from repository deploy keys call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository deploy keys with params (%s): ", dumpResourceData(d, dataRepositoryDeployKeys().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -84,7 +83,11 @@ func dataRepositoryDeploymentChangesRead(ctx context.Context, d *schema.Resource
 	url := fmt.Sprintf("2.0/repositories/%s/%s/environments/%s/changes", workspace, repoSlug, environmentUUID)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate environment %s for repository %s/%s", environmentUUID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -92,10 +95,6 @@ func dataRepositoryDeploymentChangesRead(ctx context.Context, d *schema.Resource
 		return diag.Errorf("no response returned from repository deployment changes call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate environment %s for repository %s/%s", environmentUUID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository deployment changes with params (%s): ", dumpResourceData(d, dataRepositoryDeploymentChanges().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -85,7 +84,11 @@ func dataRepositoryDeploymentEnvironmentVariablesRead(ctx context.Context, d *sc
 	url := fmt.Sprintf("2.0/repositories/%s/%s/environments/%s/variables", workspace, repoSlug, environmentUUID)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate environment %s for repository %s/%s", environmentUUID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -93,10 +96,6 @@ func dataRepositoryDeploymentEnvironmentVariablesRead(ctx context.Context, d *sc
 		return diag.Errorf("no response returned from repository deployment environment variables call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate environment %s for repository %s/%s", environmentUUID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository deployment environment variables with params (%s): ", dumpResourceData(d, dataRepositoryDeploymentEnvironmentVariables().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -87,7 +86,11 @@ func dataRepositoryDeploymentEnvironmentsRead(ctx context.Context, d *schema.Res
 	url := fmt.Sprintf("2.0/repositories/%s/%s/environments", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -95,10 +98,6 @@ func dataRepositoryDeploymentEnvironmentsRead(ctx context.Context, d *schema.Res
 		return diag.Errorf("no response returned from repository deployment environments call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository deployment environments with params (%s): ", dumpResourceData(d, dataRepositoryDeploymentEnvironments().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -87,7 +86,11 @@ func dataRepositoryFileHistoryRead(ctx context.Context, d *schema.ResourceData,
 	}
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate file %s in repository %s/%s", path, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -95,10 +98,6 @@ func dataRepositoryFileHistoryRead(ctx context.Context, d *schema.ResourceData,
 		return diag.Errorf("no response returned from repository file history call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate file %s in repository %s/%s", path, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository file history with params (%s): ", dumpResourceData(d, dataRepositoryFileHistory().Schema))
 	}
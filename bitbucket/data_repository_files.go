@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -84,7 +83,11 @@ func dataRepositoryFilesRead(ctx context.Context, d *schema.ResourceData, m inte
 	}
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s or ref %s", workspace, repoSlug, ref)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -92,10 +95,6 @@ func dataRepositoryFilesRead(ctx context.Context, d *schema.ResourceData, m inte
 		return diag.Errorf("no response returned from repository files call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s or ref %s", workspace, repoSlug, ref)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository files with params (%s): ", dumpResourceData(d, dataRepositoryFiles().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -70,7 +69,11 @@ func dataRepositoryIssueAttachmentsRead(ctx context.Context, d *schema.ResourceD
 	url := fmt.Sprintf("2.0/repositories/%s/%s/issues/%s/attachments", workspace, repoSlug, issueID)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate issue %s in repository %s/%s", issueID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -78,10 +81,6 @@ func dataRepositoryIssueAttachmentsRead(ctx context.Context, d *schema.ResourceD
 		return diag.Errorf("no response returned from repository issue attachments call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate issue %s in repository %s/%s", issueID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository issue attachments with params (%s): ", dumpResourceData(d, dataRepositoryIssueAttachments().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -77,7 +76,11 @@ func dataRepositoryIssueExportRead(ctx context.Context, d *schema.ResourceData,
 	url := fmt.Sprintf("2.0/repositories/%s/%s/issues/export/%s", workspace, repoSlug, exportID)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate export %s for repository %s/%s", exportID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -85,10 +88,6 @@ func dataRepositoryIssueExportRead(ctx context.Context, d *schema.ResourceData,
 		return diag.Errorf("no response returned from repository issue export call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate export %s for repository %s/%s", exportID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository issue export with params (%s): ", dumpResourceData(d, dataRepositoryIssueExport().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -61,7 +60,11 @@ func dataRepositoryIssueImportRead(ctx context.Context, d *schema.ResourceData,
 
 	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/issues/import", workspace, repoSlug)
 
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s or issue import", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -70,10 +73,6 @@ func dataRepositoryIssueImportRead(ctx context.Context, d *schema.ResourceData,
 		return diag.Errorf("no response returned from issue import call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s or issue import", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading issue import: empty response body")
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -69,7 +68,11 @@ func dataRepositoryIssueWatchesRead(ctx context.Context, d *schema.ResourceData,
 	url := fmt.Sprintf("2.0/repositories/%s/%s/issues/%s/watches", workspace, repoSlug, issueID)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate issue %s in repository %s/%s", issueID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -77,10 +80,6 @@ func dataRepositoryIssueWatchesRead(ctx context.Context, d *schema.ResourceData,
 		return diag.Errorf("no response returned from repository issue watches call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate issue %s in repository %s/%s", issueID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository issue watches with params (%s): ", dumpResourceData(d, dataRepositoryIssueWatches().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -85,7 +84,11 @@ func dataRepositoryOverrideSettingsRead(ctx context.Context, d *schema.ResourceD
 
 	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/override-settings", workspace, repoSlug)
 
-	res, err := client.GetAll(endpoint)
+	res, err := client.GetAllContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s or override settings", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -94,10 +97,6 @@ func dataRepositoryOverrideSettingsRead(ctx context.Context, d *schema.ResourceD
 		return diag.Errorf("no response returned from repository override settings call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s or override settings", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository override settings: empty response body")
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -64,7 +63,11 @@ func dataRepositoryPatchesRead(ctx context.Context, d *schema.ResourceData, m in
 	url := fmt.Sprintf("2.0/repositories/%s/%s/patches", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -72,10 +75,6 @@ func dataRepositoryPatchesRead(ctx context.Context, d *schema.ResourceData, m in
 		return diag.Errorf("no response returned from repository patches call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository patches with params (%s): ", dumpResourceData(d, dataRepositoryPatches().Schema))
 	}
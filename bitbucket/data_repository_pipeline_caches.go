@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -80,7 +79,11 @@ func dataRepositoryPipelineCachesRead(ctx context.Context, d *schema.ResourceDat
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines_config/caches", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -88,10 +91,6 @@ func dataRepositoryPipelineCachesRead(ctx context.Context, d *schema.ResourceDat
 		return diag.Errorf("no response returned from repository pipeline caches call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository pipeline caches with params (%s): ", dumpResourceData(d, dataRepositoryPipelineCaches().Schema))
 	}
@@ -132,12 +131,22 @@ type RepositoryPipelineCache struct {
 	Name         string                 `json:"name"`
 	Path         string                 `json:"path"`
 	Size         int                    `json:"size"`
+	FileSize     int64                  `json:"file_size_bytes"`
 	LastAccessed string                 `json:"last_accessed"`
 	CreatedOn    string                 `json:"created_on"`
 	UpdatedOn    string                 `json:"updated_on"`
 	Links        map[string]interface{} `json:"links"`
 }
 
+// bytes returns the size of the cache. Bitbucket reports it as
+// `file_size_bytes`; `size` is kept for responses that still use it.
+func (c *RepositoryPipelineCache) bytes() int64 {
+	if c.FileSize != 0 {
+		return c.FileSize
+	}
+	return int64(c.Size)
+}
+
 // Flattens the repository pipeline caches information
 func flattenRepositoryPipelineCaches(c *RepositoryPipelineCachesResponse, d *schema.ResourceData) {
 	if c == nil {
@@ -150,7 +159,7 @@ func flattenRepositoryPipelineCaches(c *RepositoryPipelineCachesResponse, d *sch
 			"uuid":          cache.UUID,
 			"name":          cache.Name,
 			"path":          cache.Path,
-			"size":          cache.Size,
+			"size":          int(cache.bytes()),
 			"last_accessed": cache.LastAccessed,
 			"created_on":    cache.CreatedOn,
 			"updated_on":    cache.UpdatedOn,
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -43,7 +42,11 @@ func dataRepositoryPipelineRunnerRead(ctx context.Context, d *schema.ResourceDat
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines-config/runners/%s", workspace, repoSlug, runnerUUID)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate runner %s in repository %s/%s", runnerUUID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -51,10 +54,6 @@ func dataRepositoryPipelineRunnerRead(ctx context.Context, d *schema.ResourceDat
 		return diag.Errorf("no response returned from repository pipeline runner call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate runner %s in repository %s/%s", runnerUUID, workspace, repoSlug)
-	}
-
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
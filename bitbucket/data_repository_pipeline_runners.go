@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -44,7 +43,11 @@ func dataRepositoryPipelineRunnersRead(ctx context.Context, d *schema.ResourceDa
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines-config/runners", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -52,10 +55,6 @@ func dataRepositoryPipelineRunnersRead(ctx context.Context, d *schema.ResourceDa
 		return diag.Errorf("no response returned from repository pipeline runners call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -87,7 +86,11 @@ func dataRepositoryPipelineSchedulesRead(ctx context.Context, d *schema.Resource
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines_config/schedules", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -95,10 +98,6 @@ func dataRepositoryPipelineSchedulesRead(ctx context.Context, d *schema.Resource
 		return diag.Errorf("no response returned from repository pipeline schedules call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository pipeline schedules with params (%s): ", dumpResourceData(d, dataRepositoryPipelineSchedules().Schema))
 	}
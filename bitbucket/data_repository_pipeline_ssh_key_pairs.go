@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -80,7 +79,11 @@ func dataRepositoryPipelineSSHKeyPairsRead(ctx context.Context, d *schema.Resour
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines_config/ssh/key_pair", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -88,10 +91,6 @@ func dataRepositoryPipelineSSHKeyPairsRead(ctx context.Context, d *schema.Resour
 		return diag.Errorf("no response returned from repository pipeline SSH key pairs call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository pipeline SSH key pairs with params (%s): ", dumpResourceData(d, dataRepositoryPipelineSSHKeyPairs().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -73,7 +72,11 @@ func dataRepositoryPullRequestApprovalsRead(ctx context.Context, d *schema.Resou
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s/approve", workspace, repoSlug, pullRequestID)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate pull request %s in repository %s/%s", pullRequestID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -81,10 +84,6 @@ func dataRepositoryPullRequestApprovalsRead(ctx context.Context, d *schema.Resou
 		return diag.Errorf("no response returned from repository pull request approvals call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate pull request %s in repository %s/%s", pullRequestID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository pull request approvals with params (%s): ", dumpResourceData(d, dataRepositoryPullRequestApprovals().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -102,7 +101,11 @@ func dataRepositoryPullRequestCommentsRead(ctx context.Context, d *schema.Resour
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s/comments", workspace, repoSlug, pullRequestID)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate pull request %s in repository %s/%s", pullRequestID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -110,10 +113,6 @@ func dataRepositoryPullRequestCommentsRead(ctx context.Context, d *schema.Resour
 		return diag.Errorf("no response returned from repository pull request comments call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate pull request %s in repository %s/%s", pullRequestID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository pull request comments with params (%s): ", dumpResourceData(d, dataRepositoryPullRequestComments().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -79,7 +78,11 @@ func dataRepositoryPullRequestMergeRead(ctx context.Context, d *schema.ResourceD
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s/merge", workspace, repoSlug, pullRequestID)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate pull request %s in repository %s/%s", pullRequestID, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -87,10 +90,6 @@ func dataRepositoryPullRequestMergeRead(ctx context.Context, d *schema.ResourceD
 		return diag.Errorf("no response returned from repository pull request merge call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate pull request %s in repository %s/%s", pullRequestID, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository pull request merge with params (%s): ", dumpResourceData(d, dataRepositoryPullRequestMerge().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -106,7 +105,11 @@ func dataRepositorySettingsRead(ctx context.Context, d *schema.ResourceData, m i
 	url := fmt.Sprintf("2.0/repositories/%s/%s", workspace, repoSlug)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -114,10 +117,6 @@ func dataRepositorySettingsRead(ctx context.Context, d *schema.ResourceData, m i
 		return diag.Errorf("no response returned from repository settings call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate repository %s/%s", workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading repository settings with params (%s): ", dumpResourceData(d, dataRepositorySettings().Schema))
 	}
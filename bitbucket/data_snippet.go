@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -146,7 +145,11 @@ func dataSnippetRead(ctx context.Context, d *schema.ResourceData, m interface{})
 
 	endpoint := fmt.Sprintf("2.0/snippets/%s/%s", workspace, encodedID)
 
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate snippet %s in workspace %s", encodedID, workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -155,10 +158,6 @@ func dataSnippetRead(ctx context.Context, d *schema.ResourceData, m interface{})
 		return diag.Errorf("no response returned from snippet call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate snippet %s in workspace %s", encodedID, workspace)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading snippet: empty response body")
 	}
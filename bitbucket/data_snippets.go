@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -150,7 +149,11 @@ func dataSnippetsRead(ctx context.Context, d *schema.ResourceData, m interface{}
 		endpoint = "2.0/snippets"
 	}
 
-	res, err := client.GetAll(endpoint)
+	res, err := client.GetAllContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate snippets for workspace %s", workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -159,10 +162,6 @@ func dataSnippetsRead(ctx context.Context, d *schema.ResourceData, m interface{}
 		return diag.Errorf("no response returned from snippets call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate snippets for workspace %s", workspace)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading snippets: empty response body")
 	}
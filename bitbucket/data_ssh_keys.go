@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -78,7 +77,11 @@ func dataSSHKeysRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	url := fmt.Sprintf("2.0/workspaces/%s/ssh-keys", workspace)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate workspace %s", workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -86,10 +89,6 @@ func dataSSHKeysRead(ctx context.Context, d *schema.ResourceData, m interface{})
 		return diag.Errorf("no response returned from SSH keys call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate workspace %s", workspace)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading SSH keys with params (%s): ", dumpResourceData(d, dataSSHKeys().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/DrFaust92/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -87,7 +86,11 @@ func dataTagRead(ctx context.Context, d *schema.ResourceData, m interface{}) dia
 	)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate tag %s in repository %s/%s", tagName, workspace, repoSlug)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -95,10 +98,6 @@ func dataTagRead(ctx context.Context, d *schema.ResourceData, m interface{}) dia
 		return diag.Errorf("no response returned from tag call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate tag %s in repository %s/%s", tagName, workspace, repoSlug)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading tag information with params (%s): ", dumpResourceData(d, dataTag().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -17,10 +16,18 @@ func dataTeamPipelineVariable() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataTeamPipelineVariableRead,
 		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Workspace slug or UUID",
+				ExactlyOneOf: []string{"workspace", "username"},
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
 			"username": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				Description:  "Team username",
+				Deprecated:   "Teams are workspaces now; use workspace instead",
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
 			"variable_uuid": {
@@ -66,12 +73,16 @@ func dataTeamPipelineVariable() *schema.Resource {
 func dataTeamPipelineVariableRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(Clients).httpClient
 
-	username := d.Get("username").(string)
+	workspace := teamPipelineVariablesWorkspace(d)
 	variableUUID := d.Get("variable_uuid").(string)
 
-	endpoint := fmt.Sprintf("2.0/teams/%s/pipelines_config/variables/%s", username, variableUUID)
+	endpoint := fmt.Sprintf("2.0/workspaces/%s/pipelines-config/variables/%s", workspace, variableUUID)
+
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate pipeline variable %s of workspace %s", variableUUID, workspace)
+	}
 
-	res, err := client.Get(endpoint)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -80,10 +91,6 @@ func dataTeamPipelineVariableRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.Errorf("no response returned from team pipeline variable call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate team pipeline variable %s for team %s", variableUUID, username)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading team pipeline variable: empty response body")
 	}
@@ -95,12 +102,12 @@ func dataTeamPipelineVariableRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	var variable TeamPipelineVariable
+	var variable WorkspacePipelineVariable
 	if err := json.Unmarshal(body, &variable); err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(fmt.Sprintf("%s/%s", username, variableUUID))
+	d.SetId(fmt.Sprintf("%s/%s", workspace, variableUUID))
 	d.Set("uuid", variable.UUID)
 	d.Set("key", variable.Key)
 	d.Set("value", variable.Value)
@@ -108,7 +115,7 @@ func dataTeamPipelineVariableRead(ctx context.Context, d *schema.ResourceData, m
 	d.Set("created_on", variable.CreatedOn)
 	d.Set("updated_on", variable.UpdatedOn)
 
-	log.Printf("[DEBUG] Retrieved team pipeline variable: %s for team %s", variable.Key, username)
+	log.Printf("[DEBUG] Retrieved pipeline variable: %s of workspace %s", variable.Key, workspace)
 
 	return nil
 }
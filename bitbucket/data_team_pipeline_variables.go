@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -17,10 +16,18 @@ func dataTeamPipelineVariables() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataTeamPipelineVariablesRead,
 		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Workspace slug or UUID",
+				ExactlyOneOf: []string{"workspace", "username"},
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
 			"username": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				Description:  "Team username",
+				Deprecated:   "Teams are workspaces now; use workspace instead",
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
 			"variables": {
@@ -68,11 +75,15 @@ func dataTeamPipelineVariables() *schema.Resource {
 func dataTeamPipelineVariablesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(Clients).httpClient
 
-	username := d.Get("username").(string)
+	workspace := teamPipelineVariablesWorkspace(d)
 
-	endpoint := fmt.Sprintf("2.0/teams/%s/pipelines_config/variables", username)
+	endpoint := fmt.Sprintf("2.0/workspaces/%s/pipelines-config/variables?pagelen=100", workspace)
+
+	res, err := client.GetAllContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate workspace %s or workspace pipeline variables", workspace)
+	}
 
-	res, err := client.GetAll(endpoint)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -81,10 +92,6 @@ func dataTeamPipelineVariablesRead(ctx context.Context, d *schema.ResourceData,
 		return diag.Errorf("no response returned from team pipeline variables call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate team %s or team pipeline variables", username)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading team pipeline variables: empty response body")
 	}
@@ -97,10 +104,10 @@ func dataTeamPipelineVariablesRead(ctx context.Context, d *schema.ResourceData,
 	}
 
 	var variablesResponse struct {
-		Values []TeamPipelineVariable `json:"values"`
-		Next   string                 `json:"next"`
-		Size   int                    `json:"size"`
-		Page   int                    `json:"page"`
+		Values []WorkspacePipelineVariable `json:"values"`
+		Next   string                      `json:"next"`
+		Size   int                         `json:"size"`
+		Page   int                         `json:"page"`
 	}
 
 	if err := json.Unmarshal(body, &variablesResponse); err != nil {
@@ -120,20 +127,29 @@ func dataTeamPipelineVariablesRead(ctx context.Context, d *schema.ResourceData,
 		variables = append(variables, variableMap)
 	}
 
-	d.SetId(fmt.Sprintf("team-pipeline-variables-%s", username))
+	d.SetId(fmt.Sprintf("team-pipeline-variables-%s", workspace))
 	d.Set("variables", variables)
 
-	log.Printf("[DEBUG] Found %d pipeline variables for team %s", len(variables), username)
+	log.Printf("[DEBUG] Found %d pipeline variables for workspace %s", len(variables), workspace)
 
 	return nil
 }
 
-// TeamPipelineVariable represents a team pipeline variable
-type TeamPipelineVariable struct {
-	UUID      string `json:"uuid"`
+// teamPipelineVariablesWorkspace returns the workspace, which older
+// configurations still pass as the team `username`.
+func teamPipelineVariablesWorkspace(d *schema.ResourceData) string {
+	if v, ok := d.GetOk("workspace"); ok {
+		return v.(string)
+	}
+	return d.Get("username").(string)
+}
+
+// WorkspacePipelineVariable represents a workspace pipeline variable
+type WorkspacePipelineVariable struct {
+	UUID      string `json:"uuid,omitempty"`
 	Key       string `json:"key"`
 	Value     string `json:"value"`
 	Secured   bool   `json:"secured"`
-	CreatedOn string `json:"created_on"`
-	UpdatedOn string `json:"updated_on"`
+	CreatedOn string `json:"created_on,omitempty"`
+	UpdatedOn string `json:"updated_on,omitempty"`
 }
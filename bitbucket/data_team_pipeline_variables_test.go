@@ -0,0 +1,66 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccDataSourceTeamPipelineVariables_basic(t *testing.T) {
+	dataSourceName := "data.bitbucket_team_pipeline_variables.test"
+
+	workspace := os.Getenv("BITBUCKET_TEAM")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketTeamPipelineVariablesDataConfig(workspace),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "workspace", workspace),
+					resource.TestCheckResourceAttrSet(dataSourceName, "variables.#"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketTeamPipelineVariablesDataConfig(workspace string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_workspace_variable" "test" {
+  workspace = %[1]q
+  key       = "test"
+  value     = "test"
+}
+
+data "bitbucket_team_pipeline_variables" "test" {
+  workspace = bitbucket_workspace_variable.test.workspace
+}
+`, workspace)
+}
+
+func TestTeamPipelineVariablesUseWorkspaceEndpoint(t *testing.T) {
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2.0/workspaces/acme/pipelines-config/variables" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			return
+		}
+		w.Write([]byte(`{"values":[{"uuid":"{v1}","key":"REGION","value":"eu-west-1"}]}`))
+	})
+
+	for _, attr := range []string{"workspace", "username"} {
+		d := schema.TestResourceDataRaw(t, dataTeamPipelineVariables().Schema, map[string]interface{}{attr: "acme"})
+		if diags := dataTeamPipelineVariablesRead(context.Background(), d, Clients{httpClient: *client}); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if d.Get("variables.0.key") != "REGION" {
+			t.Errorf("%s: unexpected variables: %v", attr, d.Get("variables"))
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"net/url"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -186,7 +185,11 @@ func dataTeamSearchCodeRead(ctx context.Context, d *schema.ResourceData, m inter
 
 	endpoint := fmt.Sprintf("2.0/teams/%s/search/code?%s", username, params.Encode())
 
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to search code for team %s", username)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -195,10 +198,6 @@ func dataTeamSearchCodeRead(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.Errorf("no response returned from team search call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to search code for team %s", username)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading search results: empty response body")
 	}
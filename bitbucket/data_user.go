@@ -40,7 +40,7 @@ func dataReadUser(ctx context.Context, d *schema.ResourceData, m interface{}) di
 		selectedUser = v.(string)
 	}
 
-	user, res, err := usersApi.UsersSelectedUserGet(c.AuthContext, selectedUser)
+	user, res, err := usersApi.UsersSelectedUserGet(c.requestContext(ctx), selectedUser)
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
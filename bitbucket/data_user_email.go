@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -49,7 +48,11 @@ func dataUserEmailRead(ctx context.Context, d *schema.ResourceData, m interface{
 
 	endpoint := fmt.Sprintf("2.0/user/emails/%s", email)
 
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate email %s", email)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -58,10 +61,6 @@ func dataUserEmailRead(ctx context.Context, d *schema.ResourceData, m interface{
 		return diag.Errorf("no response returned from user email call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate email %s", email)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading user email: empty response body")
 	}
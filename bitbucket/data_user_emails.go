@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -52,7 +51,11 @@ func dataUserEmailsRead(ctx context.Context, d *schema.ResourceData, m interface
 
 	endpoint := "2.0/user/emails"
 
-	res, err := client.GetAll(endpoint)
+	res, err := client.GetAllContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate user emails")
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -61,10 +64,6 @@ func dataUserEmailsRead(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.Errorf("no response returned from user emails call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate user emails")
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading user emails: empty response body")
 	}
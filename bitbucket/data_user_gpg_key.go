@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -98,7 +97,11 @@ func dataUserGpgKeyRead(ctx context.Context, d *schema.ResourceData, m interface
 
 	endpoint := fmt.Sprintf("2.0/users/%s/gpg-keys/%s", selectedUser, fingerprint)
 
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate GPG key %s for user %s", fingerprint, selectedUser)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -107,10 +110,6 @@ func dataUserGpgKeyRead(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.Errorf("no response returned from GPG key call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate GPG key %s for user %s", fingerprint, selectedUser)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading GPG key: empty response body")
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -104,7 +103,11 @@ func dataUserGpgKeysRead(ctx context.Context, d *schema.ResourceData, m interfac
 
 	endpoint := fmt.Sprintf("2.0/users/%s/gpg-keys", selectedUser)
 
-	res, err := client.GetAll(endpoint)
+	res, err := client.GetAllContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate GPG keys for user %s", selectedUser)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -113,10 +116,6 @@ func dataUserGpgKeysRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.Errorf("no response returned from GPG keys call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate GPG keys for user %s", selectedUser)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading GPG keys: empty response body")
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"net/url"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -186,7 +185,11 @@ func dataUserSearchCodeRead(ctx context.Context, d *schema.ResourceData, m inter
 
 	endpoint := fmt.Sprintf("2.0/users/%s/search/code?%s", selectedUser, params.Encode())
 
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to search code for user %s", selectedUser)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -195,10 +198,6 @@ func dataUserSearchCodeRead(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.Errorf("no response returned from user search call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to search code for user %s", selectedUser)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading search results: empty response body")
 	}
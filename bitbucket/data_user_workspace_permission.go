@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -48,7 +47,11 @@ func dataUserWorkspacePermissionRead(ctx context.Context, d *schema.ResourceData
 	url := fmt.Sprintf("2.0/user/workspaces/%s/permission", workspace)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate workspace %s membership", workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -56,10 +59,6 @@ func dataUserWorkspacePermissionRead(ctx context.Context, d *schema.ResourceData
 		return diag.Errorf("no response returned from user workspace permission call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate workspace %s membership", workspace)
-	}
-
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
@@ -83,7 +83,7 @@ func dataUserWorkspacesRead(ctx context.Context, d *schema.ResourceData, m inter
 	}
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -83,7 +83,7 @@ func dataUsersRead(ctx context.Context, d *schema.ResourceData, m interface{}) d
 	url := "2.0/users" + encodeQueryParams(params)
 
 	client := m.(Clients).httpClient
-	rawValues, err := client.GetPaginated(url)
+	rawValues, err := client.GetPaginatedContext(ctx, url)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -89,7 +88,11 @@ func dataWebhooksRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	url := fmt.Sprintf("2.0/workspaces/%s/hooks", workspace)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate workspace %s", workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -97,10 +100,6 @@ func dataWebhooksRead(ctx context.Context, d *schema.ResourceData, m interface{}
 		return diag.Errorf("no response returned from webhooks call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate workspace %s", workspace)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading webhooks with params (%s): ", dumpResourceData(d, dataWebhooks().Schema))
 	}
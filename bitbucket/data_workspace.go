@@ -38,7 +38,7 @@ func dataReadWorkspace(ctx context.Context, d *schema.ResourceData, m interface{
 	workspaceApi := c.ApiClient.WorkspacesApi
 
 	workspace := d.Get("workspace").(string)
-	workspaceReq, res, err := workspaceApi.WorkspacesWorkspaceGet(c.AuthContext, workspace)
+	workspaceReq, res, err := workspaceApi.WorkspacesWorkspaceGet(c.requestContext(ctx), workspace)
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
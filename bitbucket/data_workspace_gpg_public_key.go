@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -36,7 +35,11 @@ func dataWorkspaceGpgPublicKeyRead(ctx context.Context, d *schema.ResourceData,
 	url := fmt.Sprintf("2.0/workspaces/%s/settings/gpg/public-key", workspace)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate GPG public key for workspace %s", workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -44,10 +47,6 @@ func dataWorkspaceGpgPublicKeyRead(ctx context.Context, d *schema.ResourceData,
 		return diag.Errorf("no response returned from workspace GPG public key call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate GPG public key for workspace %s", workspace)
-	}
-
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
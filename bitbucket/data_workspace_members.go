@@ -61,7 +61,7 @@ func dataReadWorkspaceMembers(ctx context.Context, d *schema.ResourceData, m int
 	options := bitbucket.WorkspacesApiWorkspacesWorkspaceMembersGetOpts{}
 
 	for {
-		flattenAccountsReq, res, err := workspaceApi.WorkspacesWorkspaceMembersGet(c.AuthContext, workspace, &options)
+		flattenAccountsReq, res, err := workspaceApi.WorkspacesWorkspaceMembersGet(c.requestContext(ctx), workspace, &options)
 		if err := handleClientError(res, err); err != nil {
 			return diag.FromErr(err)
 		}
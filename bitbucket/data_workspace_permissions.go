@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -73,7 +72,11 @@ func dataWorkspacePermissionsRead(ctx context.Context, d *schema.ResourceData, m
 	url := fmt.Sprintf("2.0/workspaces/%s/permissions", workspace)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate workspace %s", workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -81,10 +84,6 @@ func dataWorkspacePermissionsRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.Errorf("no response returned from workspace permissions call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate workspace %s", workspace)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading workspace permissions with params (%s): ", dumpResourceData(d, dataWorkspacePermissions().Schema))
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -38,7 +37,11 @@ func dataWorkspacePipelineRunnerRead(ctx context.Context, d *schema.ResourceData
 	url := fmt.Sprintf("2.0/workspaces/%s/pipelines-config/runners/%s", workspace, runnerUUID)
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(url)
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate runner %s in workspace %s", runnerUUID, workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -46,10 +49,6 @@ func dataWorkspacePipelineRunnerRead(ctx context.Context, d *schema.ResourceData
 		return diag.Errorf("no response returned from workspace pipeline runner call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate runner %s in workspace %s", runnerUUID, workspace)
-	}
-
 	if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
 	}
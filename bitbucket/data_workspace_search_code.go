@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"net/url"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -186,7 +185,11 @@ func dataWorkspaceSearchCodeRead(ctx context.Context, d *schema.ResourceData, m
 
 	endpoint := fmt.Sprintf("2.0/workspaces/%s/search/code?%s", workspace, params.Encode())
 
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to search code in workspace %s", workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -195,10 +198,6 @@ func dataWorkspaceSearchCodeRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.Errorf("no response returned from workspace search call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to search code in workspace %s", workspace)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading search results: empty response body")
 	}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -64,7 +63,11 @@ func dataWorkspaceVariablesRead(ctx context.Context, d *schema.ResourceData, m i
 	url := fmt.Sprintf("2.0/workspaces/%s/pipelines-config/variables", workspace)
 
 	client := m.(Clients).httpClient
-	res, err := client.GetAll(url)
+	res, err := client.GetAllContext(ctx, url)
+	if IsNotFound(err) {
+		return diag.Errorf("unable to locate workspace %s", workspace)
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -72,10 +75,6 @@ func dataWorkspaceVariablesRead(ctx context.Context, d *schema.ResourceData, m i
 		return diag.Errorf("no response returned from workspace variables call. Make sure your credentials are accurate.")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return diag.Errorf("unable to locate workspace %s", workspace)
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading workspace variables with params (%s): ", dumpResourceData(d, dataWorkspaceVariables().Schema))
 	}
@@ -75,7 +75,7 @@ func dataWorkspacesRead(ctx context.Context, d *schema.ResourceData, m interface
 	url := "2.0/workspaces" + encodeQueryParams(params)
 
 	client := m.(Clients).httpClient
-	rawValues, err := client.GetPaginated(url)
+	rawValues, err := client.GetPaginatedContext(ctx, url)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -2,28 +2,137 @@ package bitbucket
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// Error represents a error from the bitbucket api. It is returned by
+// Client.Do for any non-2xx response, and handleClientError converts errors
+// from the generated API client into it, so that both HTTP paths share one
+// error model.
+type Error struct {
+	APIError struct {
+		Message string `json:"message,omitempty"`
+		// Detail is either a string or an object (for example the required
+		// and granted scopes of a 403), so it is kept as raw JSON.
+		Detail json.RawMessage `json:"detail,omitempty"`
+		// Fields maps request attributes to the validation errors Bitbucket
+		// reported for them.
+		Fields map[string]json.RawMessage `json:"fields,omitempty"`
+	} `json:"error,omitempty"`
+	Type       string `json:"type,omitempty"`
+	StatusCode int
+	Endpoint   string
+	Method     string
+	RequestID  string
+}
+
+func (e Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "API Error: %d", e.StatusCode)
+	if e.Method != "" {
+		fmt.Fprintf(&b, " %s", e.Method)
+	}
+	if e.Endpoint != "" {
+		fmt.Fprintf(&b, " %s", e.Endpoint)
+	}
+	if e.APIError.Message != "" {
+		fmt.Fprintf(&b, " %s", e.APIError.Message)
+	}
+	if detail := e.Detail(); detail != "" {
+		fmt.Fprintf(&b, ": %s", detail)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request ID: %s)", e.RequestID)
+	}
+	return b.String()
+}
+
+// Detail returns Bitbucket's `error.detail` as text.
+func (e Error) Detail() string {
+	return rawMessageString(e.APIError.Detail)
+}
+
+// FieldErrors returns the validation messages Bitbucket reported per request
+// attribute (`error.fields`).
+func (e Error) FieldErrors() map[string][]string {
+	if len(e.APIError.Fields) == 0 {
+		return nil
+	}
+
+	fields := make(map[string][]string, len(e.APIError.Fields))
+	for name, raw := range e.APIError.Fields {
+		var messages []string
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			messages = []string{rawMessageString(raw)}
+		}
+		fields[name] = messages
+	}
+	return fields
+}
+
+// rawMessageString renders a JSON value as text: strings are unquoted, any
+// other value is returned as compact JSON.
+func rawMessageString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// newAPIError builds an Error from a failed response and its already-read body.
+func newAPIError(resp *http.Response, method, endpoint string, body []byte) Error {
+	apiError := Error{
+		StatusCode: resp.StatusCode,
+		Endpoint:   endpoint,
+		Method:     method,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+
+	if err := json.Unmarshal(body, &apiError); err != nil {
+		apiError.APIError.Message = strings.TrimSpace(string(body))
+	}
+	if apiError.APIError.Message == "" && apiError.Detail() == "" {
+		apiError.APIError.Message = http.StatusText(resp.StatusCode)
+	}
+
+	return apiError
+}
+
 func handleClientError(httpResponse *http.Response, err error) error {
-	if httpResponse == nil || httpResponse.StatusCode < 400 {
+	if httpResponse == nil {
+		return err
+	}
+	if httpResponse.StatusCode < 400 {
 		return nil
 	}
 
+	var apiError Error
+	if errors.As(err, &apiError) {
+		return apiError
+	}
+
 	clientHttpError, ok := err.(bitbucket.GenericSwaggerError)
 	if ok {
-		var errorBody string
-		var bitbucketHttpError bitbucket.ModelError
-		if err := json.Unmarshal(clientHttpError.Body(), &bitbucketHttpError); err != nil {
-			errorBody = string(clientHttpError.Body()[:])
-		} else {
-			errorBody = bitbucketHttpError.Error_.Message
+		var method, endpoint string
+		if req := httpResponse.Request; req != nil {
+			method = req.Method
+			endpoint = strings.TrimPrefix(req.URL.RequestURI(), "/")
 		}
-
-		return fmt.Errorf("%s: %s", httpResponse.Status, errorBody)
+		return newAPIError(httpResponse, method, endpoint, clientHttpError.Body())
 	}
 
 	if err != nil {
@@ -32,3 +141,116 @@ func handleClientError(httpResponse *http.Response, err error) error {
 
 	return nil
 }
+
+// asAPIError extracts the API error carried by err, if any. Besides Error it
+// understands the GenericSwaggerError returned by the generated API client,
+// so the predicates below can be applied to errors from either HTTP path.
+func asAPIError(err error) (Error, bool) {
+	if err == nil {
+		return Error{}, false
+	}
+
+	var apiError Error
+	if errors.As(err, &apiError) {
+		return apiError, true
+	}
+
+	var swaggerError bitbucket.GenericSwaggerError
+	if errors.As(err, &swaggerError) {
+		// The generated client uses the response status line (e.g.
+		// "404 Not Found") as the error message.
+		status, _, _ := strings.Cut(swaggerError.Error(), " ")
+		code, convErr := strconv.Atoi(status)
+		if convErr != nil {
+			return Error{}, false
+		}
+		resp := &http.Response{StatusCode: code, Header: http.Header{}}
+		return newAPIError(resp, "", "", swaggerError.Body()), true
+	}
+
+	return Error{}, false
+}
+
+func hasStatus(err error, statusCode int) bool {
+	apiError, ok := asAPIError(err)
+	return ok && apiError.StatusCode == statusCode
+}
+
+// IsNotFound reports whether err is an API error with HTTP status 404.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsForbidden reports whether err is an API error with HTTP status 403, which
+// Bitbucket returns when the credentials lack a required scope or permission.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsConflict reports whether err is an API error with HTTP status 409.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsRateLimited reports whether err is an API error with HTTP status 429.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// apiErrorDiagnostics converts err into diagnostics. When Bitbucket reports
+// validation errors for individual request fields that correspond to
+// attributes in the resource configuration, each of them becomes a diagnostic
+// pointing at that attribute, so Terraform highlights the offending argument.
+func apiErrorDiagnostics(err error, d *schema.ResourceData) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+
+	apiError, ok := asAPIError(err)
+	if !ok || d == nil {
+		return diag.FromErr(err)
+	}
+
+	fields := apiError.FieldErrors()
+	if len(fields) == 0 {
+		return diag.FromErr(err)
+	}
+
+	// The raw config is typed after the resource schema even when null.
+	configType := d.GetRawConfig().Type()
+	hasAttribute := func(name string) bool {
+		return configType.IsObjectType() && configType.HasAttribute(name)
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var diags diag.Diagnostics
+	var unmatched []string
+	for _, name := range names {
+		message := strings.Join(fields[name], " ")
+		if !hasAttribute(name) {
+			unmatched = append(unmatched, fmt.Sprintf("%s: %s", name, message))
+			continue
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("Invalid value for %q", name),
+			Detail:        message,
+			AttributePath: cty.GetAttrPath(name),
+		})
+	}
+
+	if len(diags) == 0 || len(unmatched) > 0 {
+		diags = append(diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  err.Error(),
+			Detail:   strings.Join(unmatched, "\n"),
+		}}, diags...)
+	}
+
+	return diags
+}
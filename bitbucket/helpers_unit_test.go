@@ -1,12 +1,25 @@
 package bitbucket
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/DrFaust92/bitbucket-go-client"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // stubTransport serves canned responses keyed by request path (+ query).
@@ -47,38 +60,427 @@ func TestGetAllMergesPages(t *testing.T) {
 	}
 }
 
-func TestRetryAfterDelay(t *testing.T) {
+// newMockServerClient starts a local stand-in for the Bitbucket API backed by
+// handler and returns a Client pointed at it via BaseURL.
+func newMockServerClient(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &Client{BaseURL: server.URL, HTTPClient: server.Client()}, server
+}
+
+func TestClientBaseURL(t *testing.T) {
+	var gotPath string
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte(`{}`))
+	})
+
+	if _, err := client.Get("2.0/user"); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if gotPath != "/2.0/user" {
+		t.Errorf("request path = %q, want %q", gotPath, "/2.0/user")
+	}
+}
+
+func TestGetAllFollowsCustomHostLinks(t *testing.T) {
+	var server *httptest.Server
+	client, server := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.RawQuery {
+		case "":
+			w.Write([]byte(`{"values":[{"n":1}],"next":"` + server.URL + `/proxy/2.0/foo?page=2"}`))
+		case "page=2":
+			w.Write([]byte(`{"values":[{"n":2}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	client.BaseURL = server.URL + "/proxy/"
+
+	res, err := client.GetAll("2.0/foo")
+	if err != nil {
+		t.Fatalf("GetAll returned error: %v", err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	if got, want := string(body), `{"values":[{"n":1},{"n":2}]}`; got != want {
+		t.Errorf("GetAll merged body mismatch:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, MinWait: time.Second, MaxWait: 5 * time.Second}
+
 	// Honour Retry-After header when present.
-	resp := &http.Response{Header: http.Header{"Retry-After": []string{"5"}}}
-	if got := retryAfterDelay(resp, 0); got != 5*time.Second {
-		t.Errorf("with Retry-After=5 want 5s, got %s", got)
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	if got := policy.backoff(resp, 0); got != 7*time.Second {
+		t.Errorf("with Retry-After=7 want 7s, got %s", got)
 	}
 
-	// Fall back to exponential backoff otherwise.
+	// Fall back to jittered exponential backoff, capped at MaxWait.
 	empty := &http.Response{Header: http.Header{}}
-	if got := retryAfterDelay(empty, 0); got != retryBaseDelay {
-		t.Errorf("attempt 0 want %s, got %s", retryBaseDelay, got)
+	cases := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, 500 * time.Millisecond, time.Second},
+		{2, 2 * time.Second, 4 * time.Second},
+		{10, 2500 * time.Millisecond, 5 * time.Second},
+	}
+	for _, c := range cases {
+		for i := 0; i < 20; i++ {
+			if got := policy.backoff(empty, c.attempt); got < c.min || got > c.max {
+				t.Errorf("attempt %d: backoff %s outside [%s, %s]", c.attempt, got, c.min, c.max)
+			}
+		}
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	cases := []struct {
+		method string
+		status int
+		err    error
+		want   bool
+	}{
+		{http.MethodGet, http.StatusTooManyRequests, nil, true},
+		{http.MethodPost, http.StatusTooManyRequests, nil, true},
+		{http.MethodGet, http.StatusBadGateway, nil, true},
+		{http.MethodPut, http.StatusServiceUnavailable, nil, true},
+		{http.MethodDelete, http.StatusGatewayTimeout, nil, true},
+		{http.MethodPost, http.StatusBadGateway, nil, false},
+		{http.MethodGet, http.StatusNotImplemented, nil, false},
+		{http.MethodGet, http.StatusNotFound, nil, false},
+		{http.MethodGet, 0, io.ErrUnexpectedEOF, true},
+		{http.MethodPost, 0, io.ErrUnexpectedEOF, false},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, "https://api.bitbucket.org/2.0/user", nil)
+		var resp *http.Response
+		if c.err == nil {
+			resp = &http.Response{StatusCode: c.status}
+		}
+		if got := policy.shouldRetry(req, resp, c.err); got != c.want {
+			t.Errorf("%s status=%d err=%v: shouldRetry = %v, want %v", c.method, c.status, c.err, got, c.want)
+		}
+	}
+}
+
+func TestRetryTransportReplaysBody(t *testing.T) {
+	var attempts int
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{MaxRetries: 3, MinWait: time.Millisecond, MaxWait: time.Millisecond}
+	client := &Client{
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Transport: newRetryTransport(nil, policy)},
+	}
+
+	if _, err := client.Put("2.0/foo", bytes.NewBufferString(`{"a":1}`)); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	for i, b := range bodies {
+		if b != `{"a":1}` {
+			t.Errorf("attempt %d sent body %q", i+1, b)
+		}
+	}
+
+	// Non-idempotent requests are not replayed after a 5xx.
+	attempts = 0
+	if _, err := client.Post("2.0/foo", bytes.NewBufferString(`{}`)); err == nil {
+		t.Error("expected Post to fail with 503")
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt for POST, got %d", attempts)
+	}
+}
+
+func TestRetryTransportHonoursCancellation(t *testing.T) {
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	policy := RetryPolicy{MaxRetries: 5, MinWait: time.Minute, MaxWait: time.Minute}
+	client.HTTPClient = &http.Client{Transport: newRetryTransport(nil, policy)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetContext(ctx, "2.0/user")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("backoff was not interrupted by the context, took %s", elapsed)
+	}
+}
+
+func TestRequestContextCarriesAuth(t *testing.T) {
+	authCtx := context.WithValue(context.Background(), bitbucket.ContextAccessToken, "token")
+	config := ProviderConfig{AuthContext: authCtx}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reqCtx := config.requestContext(ctx)
+	if got := reqCtx.Value(bitbucket.ContextAccessToken); got != "token" {
+		t.Errorf("expected access token to be carried over, got %v", got)
+	}
+
+	cancel()
+	if reqCtx.Err() == nil {
+		t.Error("expected request context to be cancelled with its parent")
+	}
+}
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := newRateLimiter(2)
+	limiter.now = func() time.Time { return now }
+
+	// The bucket starts full with a burst of two requests.
+	for i := 0; i < 2; i++ {
+		if wait := limiter.reserve(); wait != 0 {
+			t.Fatalf("request %d: expected no wait, got %s", i+1, wait)
+		}
+	}
+	if wait := limiter.reserve(); wait != 500*time.Millisecond {
+		t.Errorf("expected 500ms wait once the bucket is empty, got %s", wait)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if wait := limiter.reserve(); wait != 0 {
+		t.Errorf("expected a token after refill, got wait %s", wait)
+	}
+}
+
+func TestRateLimiterUnlimitedByDefault(t *testing.T) {
+	limiter := newRateLimiter(0)
+	for i := 0; i < 100; i++ {
+		if wait := limiter.reserve(); wait != 0 {
+			t.Fatalf("expected no wait without a configured rate, got %s", wait)
+		}
+	}
+}
+
+func TestRateLimiterAdaptsToHeaders(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := newRateLimiter(0)
+	limiter.now = func() time.Time { return now }
+
+	// A nearly exhausted quota is spread over the time left in the window.
+	limiter.observe(&http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"X-Ratelimit-Limit":     []string{"1000"},
+		"X-Ratelimit-Remaining": []string{"10"},
+		"X-Ratelimit-Reset":     []string{"20"},
+	}})
+	if got := limiter.currentRate(now); got != 0.5 {
+		t.Errorf("expected adaptive rate of 0.5 req/s, got %v", got)
+	}
+
+	// An exhausted quota pauses all requests until the reset.
+	limiter.observe(&http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"X-Ratelimit-Remaining": []string{"0"},
+		"X-Ratelimit-Reset":     []string{strconv.FormatInt(now.Add(30*time.Second).Unix(), 10)},
+	}})
+	if wait := limiter.reserve(); wait != 30*time.Second {
+		t.Errorf("expected to wait for the reset (30s), got %s", wait)
+	}
+
+	// A 429 pauses for the Retry-After period.
+	now = now.Add(time.Minute)
+	limiter.observe(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{
+		"Retry-After": []string{"5"},
+	}})
+	if wait := limiter.reserve(); wait != 5*time.Second {
+		t.Errorf("expected to wait for Retry-After (5s), got %s", wait)
+	}
+
+	// Bitbucket's near-limit flag slows requests down.
+	now = now.Add(time.Minute)
+	limiter.observe(&http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"X-Ratelimit-Nearlimit": []string{"true"},
+	}})
+	if got := limiter.currentRate(now); got != nearLimitRate {
+		t.Errorf("expected near-limit rate %v, got %v", nearLimitRate, got)
+	}
+}
+
+func TestRateLimiterIgnoresPlentifulQuota(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := newRateLimiter(0)
+	limiter.now = func() time.Time { return now }
+
+	// Plenty of quota left does not slow requests down, even when the window
+	// resets far in the future.
+	limiter.observe(&http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"X-Ratelimit-Limit":     []string{"1000"},
+		"X-Ratelimit-Remaining": []string{"900"},
+		"X-Ratelimit-Reset":     []string{"3000"},
+	}})
+	if got := limiter.currentRate(now); got != 0 {
+		t.Errorf("expected no rate limit, got %v req/s", got)
+	}
+
+	// A cap from an earlier near-limit response is lifted once the quota has
+	// been replenished.
+	limiter.observe(&http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"X-Ratelimit-Nearlimit": []string{"true"},
+	}})
+	limiter.observe(&http.Response{StatusCode: http.StatusOK, Header: http.Header{
+		"X-Ratelimit-Limit":     []string{"1000"},
+		"X-Ratelimit-Remaining": []string{"1000"},
+		"X-Ratelimit-Reset":     []string{"3600"},
+	}})
+	if got := limiter.currentRate(now); got != 0 {
+		t.Errorf("expected the near-limit cap to be lifted, got %v req/s", got)
+	}
+}
+
+func TestClientDoReturnsStructuredError(t *testing.T) {
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type":"error","error":{"message":"Bad request","detail":"name is invalid","fields":{"name":["This field is required."]}}}`))
+	})
+
+	_, err := client.Post("2.0/repositories/ws/repo", bytes.NewBufferString(`{}`))
+	apiError, ok := asAPIError(err)
+	if !ok {
+		t.Fatalf("expected an API error, got %T: %v", err, err)
+	}
+	if apiError.Method != http.MethodPost || apiError.Endpoint != "2.0/repositories/ws/repo" || apiError.RequestID != "req-123" {
+		t.Errorf("unexpected request metadata: %+v", apiError)
+	}
+	if apiError.Detail() != "name is invalid" {
+		t.Errorf("unexpected detail %q", apiError.Detail())
+	}
+	if got := apiError.FieldErrors(); !reflect.DeepEqual(got, map[string][]string{"name": {"This field is required."}}) {
+		t.Errorf("unexpected field errors %#v", got)
 	}
-	if got := retryAfterDelay(empty, 2); got != 4*retryBaseDelay {
-		t.Errorf("attempt 2 want %s, got %s", 4*retryBaseDelay, got)
+	want := "API Error: 400 POST 2.0/repositories/ws/repo Bad request: name is invalid (request ID: req-123)"
+	if err.Error() != want {
+		t.Errorf("Error() mismatch:\n got: %s\nwant: %s", err.Error(), want)
+	}
+}
+
+func TestErrorPredicates(t *testing.T) {
+	cases := []struct {
+		status int
+		check  func(error) bool
+	}{
+		{http.StatusNotFound, IsNotFound},
+		{http.StatusForbidden, IsForbidden},
+		{http.StatusConflict, IsConflict},
+		{http.StatusTooManyRequests, IsRateLimited},
+	}
+	for _, c := range cases {
+		err := fmt.Errorf("wrapped: %w", Error{StatusCode: c.status})
+		if !c.check(err) {
+			t.Errorf("predicate for %d did not match", c.status)
+		}
+		if c.check(Error{StatusCode: http.StatusInternalServerError}) {
+			t.Errorf("predicate for %d matched a 500", c.status)
+		}
+	}
+	if IsNotFound(nil) || IsNotFound(errors.New("boom")) {
+		t.Error("IsNotFound should not match non-API errors")
+	}
+}
+
+func TestGeneratedClientErrors(t *testing.T) {
+	_, server := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"type":"error","error":{"message":"Repository not found"}}`))
+	})
+	conf := bitbucket.NewConfiguration()
+	conf.BasePath = apiBasePath(server.URL)
+	conf.HTTPClient = server.Client()
+	apiClient := bitbucket.NewAPIClient(conf)
+
+	_, res, err := apiClient.RepositoriesApi.RepositoriesWorkspaceRepoSlugGet(context.Background(), "repo", "ws")
+	if !IsNotFound(err) {
+		t.Fatalf("expected IsNotFound for generated client error, got %v", err)
+	}
+
+	err = handleClientError(res, err)
+	apiError, ok := err.(Error)
+	if !ok {
+		t.Fatalf("expected handleClientError to return Error, got %T", err)
+	}
+	if apiError.Method != http.MethodGet || apiError.Endpoint != "2.0/repositories/ws/repo" || apiError.APIError.Message != "Repository not found" {
+		t.Errorf("unexpected error: %+v", apiError)
+	}
+}
+
+func TestAPIErrorDiagnosticsPointAtAttributes(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceProject().Schema, map[string]interface{}{
+		"owner": "ws",
+		"name":  "proj",
+		"key":   "PROJ",
+	})
+
+	apiError := Error{StatusCode: http.StatusBadRequest}
+	apiError.APIError.Message = "Bad request"
+	apiError.APIError.Fields = map[string]json.RawMessage{
+		"key":     json.RawMessage(`["Project key already in use."]`),
+		"unknown": json.RawMessage(`"Something else."`),
+	}
+
+	diags := apiErrorDiagnostics(apiError, d)
+	if len(diags) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %d: %#v", len(diags), diags)
+	}
+	if diags[0].AttributePath != nil || diags[0].Detail != "unknown: Something else." {
+		t.Errorf("unexpected summary diagnostic: %#v", diags[0])
+	}
+	if !diags[1].AttributePath.Equals(cty.GetAttrPath("key")) || diags[1].Detail != "Project key already in use." {
+		t.Errorf("unexpected attribute diagnostic: %#v", diags[1])
+	}
+}
+
+func TestHandleClientErrorWithoutResponse(t *testing.T) {
+	if err := handleClientError(nil, io.ErrUnexpectedEOF); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected transport error to be returned, got %v", err)
+	}
+	if err := handleClientError(nil, nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
 	}
 }
 
 func TestToRelativeEndpoint(t *testing.T) {
 	cases := []struct {
 		name string
+		base string
 		in   string
 		want string
 	}{
-		{"empty", "", ""},
-		{"absolute with query", "https://api.bitbucket.org/2.0/users?page=2", "2.0/users?page=2"},
-		{"absolute no query", "https://api.bitbucket.org/2.0/repositories/ws/repo/refs/tags", "2.0/repositories/ws/repo/refs/tags"},
-		{"leading slash stripped", "https://api.bitbucket.org/2.0/workspaces", "2.0/workspaces"},
+		{"empty", BitbucketEndpoint, "", ""},
+		{"absolute with query", BitbucketEndpoint, "https://api.bitbucket.org/2.0/users?page=2", "2.0/users?page=2"},
+		{"absolute no query", BitbucketEndpoint, "https://api.bitbucket.org/2.0/repositories/ws/repo/refs/tags", "2.0/repositories/ws/repo/refs/tags"},
+		{"leading slash stripped", BitbucketEndpoint, "https://api.bitbucket.org/2.0/workspaces", "2.0/workspaces"},
+		{"custom host", "http://localhost:8080/", "http://localhost:8080/2.0/users?page=2", "2.0/users?page=2"},
+		{"custom host with path prefix", "https://proxy.example.com/bitbucket", "https://proxy.example.com/bitbucket/2.0/users?page=2", "2.0/users?page=2"},
+		{"canonical link behind proxy", "https://proxy.example.com/bitbucket/", "https://api.bitbucket.org/2.0/users?page=2", "2.0/users?page=2"},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			if got := toRelativeEndpoint(c.in); got != c.want {
-				t.Errorf("toRelativeEndpoint(%q) = %q, want %q", c.in, got, c.want)
+			if got := toRelativeEndpoint(c.base, c.in); got != c.want {
+				t.Errorf("toRelativeEndpoint(%q, %q) = %q, want %q", c.base, c.in, got, c.want)
 			}
 		})
 	}
@@ -187,3 +589,202 @@ func TestFlattenFileConflicts(t *testing.T) {
 		t.Errorf("unexpected flattened conflict: %#v", m)
 	}
 }
+
+func TestDetectAccessToken(t *testing.T) {
+	repo := func(workspace, project, slug string) string {
+		return fmt.Sprintf(`{"full_name":"%s/%s","slug":"%s","project":{"key":"%s"},"workspace":{"slug":"%s"}}`, workspace, slug, slug, project, workspace)
+	}
+	cases := []struct {
+		name      string
+		scopes    string
+		values    []string
+		wantType  AccessTokenType
+		wantRepo  string
+		wantProj  string
+		wantScope []string
+	}{
+		{"repository", "repository:admin, pipeline:variable", []string{repo("acme", "PRJ", "app")}, AccessTokenRepository, "app", "PRJ", []string{"pipeline:variable", "repository:admin"}},
+		{"project by scope", "project", []string{repo("acme", "PRJ", "app")}, AccessTokenProject, "", "PRJ", []string{"project"}},
+		{"project by repositories", "repository", []string{repo("acme", "PRJ", "app"), repo("acme", "PRJ", "lib")}, AccessTokenProject, "", "PRJ", []string{"repository"}},
+		{"workspace by scope", "project:admin", []string{repo("acme", "PRJ", "app")}, AccessTokenWorkspace, "", "", []string{"project:admin"}},
+		{"workspace by repositories", "", []string{repo("acme", "PRJ", "app"), repo("acme", "OPS", "infra")}, AccessTokenWorkspace, "", "", nil},
+		{"unknown", "repository", nil, "", "", "", []string{"repository"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("Authorization = %q, want bearer token", got)
+				}
+				if r.URL.Query().Get("role") != "member" {
+					t.Errorf("unexpected query %q", r.URL.RawQuery)
+				}
+				if tc.scopes != "" {
+					w.Header().Set("X-OAuth-Scopes", tc.scopes)
+				}
+				fmt.Fprintf(w, `{"values":[%s]}`, strings.Join(tc.values, ","))
+			})
+			client.AccessToken = &AccessToken{Token: "secret"}
+
+			token, err := detectAccessToken(context.Background(), client, "secret")
+			if err != nil {
+				t.Fatalf("detectAccessToken returned error: %v", err)
+			}
+			if token.Type != tc.wantType || token.Repository != tc.wantRepo || token.Project != tc.wantProj {
+				t.Errorf("got %s (project %q, repository %q), want type %q project %q repository %q", token, token.Project, token.Repository, tc.wantType, tc.wantProj, tc.wantRepo)
+			}
+			if !reflect.DeepEqual(token.Scopes, tc.wantScope) {
+				t.Errorf("scopes = %v, want %v", token.Scopes, tc.wantScope)
+			}
+		})
+	}
+}
+
+func TestDetectAccessTokenRejected(t *testing.T) {
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"type":"error","error":{"message":"Invalid token"}}`))
+	})
+	client.AccessToken = &AccessToken{Token: "bad"}
+
+	if _, err := detectAccessToken(context.Background(), client, "bad"); err == nil || !strings.Contains(err.Error(), "access_token was rejected") {
+		t.Fatalf("expected rejection error, got %v", err)
+	}
+}
+
+func TestAccessTokenRequirementCheck(t *testing.T) {
+	repoToken := &AccessToken{Type: AccessTokenRepository, Workspace: "acme", Project: "PRJ", Repository: "app", Scopes: []string{"repository:admin", "pipeline:variable"}}
+	projectToken := &AccessToken{Type: AccessTokenProject, Workspace: "acme", Project: "PRJ", Scopes: []string{"project", "pullrequest:write"}}
+	workspaceToken := &AccessToken{Type: AccessTokenWorkspace, Workspace: "acme", Scopes: []string{"project:admin", "webhook"}}
+	unknownToken := &AccessToken{}
+
+	cases := []struct {
+		name     string
+		resource string
+		token    *AccessToken
+		target   accessTokenTarget
+		wantErr  string
+	}{
+		{"repository token on its repository", "bitbucket_branch_restriction", repoToken, accessTokenTarget{Workspace: "acme", Repository: "app"}, ""},
+		{"repository token on another repository", "bitbucket_branch_restriction", repoToken, accessTokenTarget{Workspace: "acme", Repository: "other"}, `cannot access repository "other"`},
+		{"repository token in another workspace", "bitbucket_repository_variable", repoToken, accessTokenTarget{Workspace: "globex", Repository: "app"}, `cannot access workspace "globex"`},
+		{"repository token missing scope", "bitbucket_hook", repoToken, accessTokenTarget{}, "lacks the webhook scope"},
+		{"repository token on a workspace resource", "bitbucket_workspace_variable", repoToken, accessTokenTarget{Workspace: "acme"}, "workspace access token"},
+		{"implied scope", "bitbucket_default_reviewers", &AccessToken{Type: AccessTokenRepository, Workspace: "acme", Repository: "app", Scopes: []string{"pullrequest:write", "repository:admin"}}, accessTokenTarget{}, ""},
+		{"project token in another project", "bitbucket_project_default_reviewers", &AccessToken{Type: AccessTokenProject, Workspace: "acme", Project: "PRJ", Scopes: []string{"project:admin"}}, accessTokenTarget{Workspace: "acme", Project: "OPS"}, `cannot access project "OPS"`},
+		{"project token on any repository of the workspace", "bitbucket_commit_file", projectToken, accessTokenTarget{Workspace: "acme", Repository: "anything"}, ""},
+		{"workspace token", "bitbucket_workspace_hook", workspaceToken, accessTokenTarget{Workspace: "ACME"}, ""},
+		{"unknown token type only checks scopes", "bitbucket_workspace_hook", unknownToken, accessTokenTarget{Workspace: "globex"}, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := accessTokenRequirements[tc.resource].check(tc.token, tc.target)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccessTokenRequirementsMatchResources(t *testing.T) {
+	provider := Provider()
+	for name, requirement := range accessTokenRequirements {
+		resource, ok := provider.ResourcesMap[name]
+		if !ok {
+			t.Errorf("%s is not a registered resource", name)
+			continue
+		}
+		if resource.CustomizeDiff == nil {
+			t.Errorf("%s has no access token check", name)
+		}
+		for _, attr := range []string{requirement.WorkspaceAttr, requirement.ProjectAttr, requirement.RepositoryAttr, requirement.RepositoryIDAttr} {
+			if attr == "" {
+				continue
+			}
+			if s, ok := resource.Schema[attr]; !ok || s.Type != schema.TypeString {
+				t.Errorf("%s has no string attribute %q", name, attr)
+			}
+		}
+	}
+}
+
+func TestParseAuthCommandOutput(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	token, err := parseAuthCommandOutput([]byte(`{"access_token":"abc","expires_in":3600}`), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "abc" || token.TokenType != "Bearer" || !token.Expiry.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected token: %+v", token)
+	}
+
+	token, err = parseAuthCommandOutput([]byte(`{"token":"def","expires_at":"2026-01-01T13:30:00Z"}`), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "def" || !token.Expiry.Equal(now.Add(90*time.Minute)) {
+		t.Errorf("unexpected token: %+v", token)
+	}
+
+	for _, output := range []string{`not json`, `{"expires_in":60}`, `{"access_token":"x","expires_at":"tomorrow"}`} {
+		if _, err := parseAuthCommandOutput([]byte(output), now); err == nil {
+			t.Errorf("expected error for %q", output)
+		} else if strings.Contains(err.Error(), `"x"`) {
+			t.Errorf("error leaks the token: %v", err)
+		}
+	}
+}
+
+func TestCommandTokenSourceRefreshes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+
+	counter := filepath.Join(t.TempDir(), "runs")
+	script := fmt.Sprintf(`n=$(cat %[1]s 2>/dev/null || echo 0); n=$((n+1)); echo $n > %[1]s; echo "{\"access_token\":\"token-$n\",\"expires_in\":1}"`, counter)
+	source := newCommandTokenSource([]string{"/bin/sh", "-c", script})
+
+	// Tokens that expire within oauth2's expiry margin are refreshed on every
+	// call, so each call runs the command again.
+	for _, want := range []string{"token-1", "token-2"} {
+		token, err := source.Token()
+		if err != nil {
+			t.Fatalf("Token returned error: %v", err)
+		}
+		if token.AccessToken != want {
+			t.Errorf("access token = %q, want %q", token.AccessToken, want)
+		}
+	}
+
+	failing := newCommandTokenSource([]string{"/bin/sh", "-c", "echo vault is sealed >&2; exit 3"})
+	if _, err := failing.Token(); err == nil || !strings.Contains(err.Error(), "vault is sealed") {
+		t.Errorf("expected error with the command's stderr, got %v", err)
+	}
+}
+
+func TestCommandTokenSourceAuthenticatesRequests(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer from-vault" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer from-vault")
+		}
+		w.Write([]byte(`{}`))
+	})
+	client.OAuthTokenSource = newCommandTokenSource([]string{"/bin/sh", "-c", `echo '{"access_token":"from-vault","expires_in":3600}'`})
+
+	if _, err := client.Get("2.0/user"); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+}
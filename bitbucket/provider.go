@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/DrFaust92/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	oauth2bitbucket "golang.org/x/oauth2/bitbucket"
 	oauth2clientcreds "golang.org/x/oauth2/clientcredentials"
 )
@@ -17,6 +20,21 @@ type ProviderConfig struct {
 	AuthContext context.Context
 }
 
+// requestContext returns ctx carrying the authentication values of
+// AuthContext, so that calls made through the generated API client are both
+// authenticated and cancelled along with the Terraform operation.
+func (c ProviderConfig) requestContext(ctx context.Context) context.Context {
+	if c.AuthContext == nil {
+		return ctx
+	}
+	for _, key := range []interface{}{bitbucket.ContextBasicAuth, bitbucket.ContextAccessToken, bitbucket.ContextOAuth2} {
+		if v := c.AuthContext.Value(key); v != nil {
+			ctx = context.WithValue(ctx, key, v)
+		}
+	}
+	return ctx
+}
+
 type Clients struct {
 	genClient  ProviderConfig
 	httpClient Client
@@ -24,18 +42,19 @@ type Clients struct {
 
 // Provider will create the necessary terraform provider to talk to the
 // Bitbucket APIs you should either specify Username and App Password, OAuth
-// Client Credentials or a valid OAuth Access Token.
+// Client Credentials, a valid OAuth Access Token or a repository, project or
+// workspace Access Token.
 //
 // See the Bitbucket authentication documentation for more:
 // https://developer.atlassian.com/cloud/bitbucket/rest/intro/#authentication
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"username": {
 				Optional:      true,
 				Type:          schema.TypeString,
 				DefaultFunc:   schema.EnvDefaultFunc("BITBUCKET_USERNAME", nil),
-				ConflictsWith: []string{"oauth_client_id", "oauth_client_secret", "oauth_token"},
+				ConflictsWith: []string{"oauth_client_id", "oauth_client_secret", "oauth_token", "access_token", "auth_command"},
 				RequiredWith:  []string{"password"},
 				Description:   "Bitbucket username for Basic Auth. Can also be set with the `BITBUCKET_USERNAME` environment variable. Requires `password`.",
 			},
@@ -44,7 +63,7 @@ func Provider() *schema.Provider {
 				Optional:      true,
 				Sensitive:     true,
 				DefaultFunc:   schema.EnvDefaultFunc("BITBUCKET_PASSWORD", nil),
-				ConflictsWith: []string{"oauth_client_id", "oauth_client_secret", "oauth_token"},
+				ConflictsWith: []string{"oauth_client_id", "oauth_client_secret", "oauth_token", "access_token", "auth_command"},
 				RequiredWith:  []string{"username"},
 				Description:   "Bitbucket app password used with `username` for Basic Auth. Can also be set with the `BITBUCKET_PASSWORD` environment variable.",
 			},
@@ -52,7 +71,7 @@ func Provider() *schema.Provider {
 				Type:          schema.TypeString,
 				Optional:      true,
 				DefaultFunc:   schema.EnvDefaultFunc("BITBUCKET_OAUTH_CLIENT_ID", nil),
-				ConflictsWith: []string{"username", "password", "oauth_token"},
+				ConflictsWith: []string{"username", "password", "oauth_token", "access_token", "auth_command"},
 				RequiredWith:  []string{"oauth_client_secret"},
 				Description:   "OAuth 2.0 client ID for the Client Credentials grant. Can also be set with the `BITBUCKET_OAUTH_CLIENT_ID` environment variable. Requires `oauth_client_secret`.",
 			},
@@ -61,7 +80,7 @@ func Provider() *schema.Provider {
 				Optional:      true,
 				Sensitive:     true,
 				DefaultFunc:   schema.EnvDefaultFunc("BITBUCKET_OAUTH_CLIENT_SECRET", nil),
-				ConflictsWith: []string{"username", "password", "oauth_token"},
+				ConflictsWith: []string{"username", "password", "oauth_token", "access_token", "auth_command"},
 				RequiredWith:  []string{"oauth_client_id"},
 				Description:   "OAuth 2.0 client secret for the Client Credentials grant. Can also be set with the `BITBUCKET_OAUTH_CLIENT_SECRET` environment variable. Requires `oauth_client_id`.",
 			},
@@ -70,35 +89,100 @@ func Provider() *schema.Provider {
 				Optional:      true,
 				Sensitive:     true,
 				DefaultFunc:   schema.EnvDefaultFunc("BITBUCKET_OAUTH_TOKEN", nil),
-				ConflictsWith: []string{"username", "password", "oauth_client_id", "oauth_client_secret"},
+				ConflictsWith: []string{"username", "password", "oauth_client_id", "oauth_client_secret", "access_token", "auth_command"},
 				Description:   "OAuth 2.0 access token. Can also be set with the `BITBUCKET_OAUTH_TOKEN` environment variable.",
 			},
+			"access_token": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("BITBUCKET_ACCESS_TOKEN", nil),
+				ConflictsWith: []string{"username", "password", "oauth_client_id", "oauth_client_secret", "oauth_token", "auth_command"},
+				Description:   "Repository, project or workspace access token. The provider detects which repository, project or workspace the token is bound to and its scopes, and fails the plan for resources the token cannot manage. Can also be set with the `BITBUCKET_ACCESS_TOKEN` environment variable.",
+			},
+			"auth_command": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MinItems:      1,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"username", "password", "oauth_client_id", "oauth_client_secret", "oauth_token", "access_token"},
+				Description:   "Program and arguments to run to obtain a token, for example a wrapper around a secrets manager. The program must print a JSON object with `access_token` and optionally `expires_at` (RFC 3339) or `expires_in` (seconds) on stdout. It is run again whenever the token is about to expire.",
+			},
+			"base_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("BITBUCKET_API_URL", BitbucketEndpoint),
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description:  "Root URL of the Bitbucket API, without the `2.0` path segment. Defaults to `https://api.bitbucket.org/`. Useful for pointing the provider at an API-compatible proxy or a local stand-in server. Can also be set with the `BITBUCKET_API_URL` environment variable.",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultMaxRetries,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of times a request is retried after a rate limit (HTTP 429), a 5xx response or a network error. 5xx responses and network errors are only retried for idempotent methods. Defaults to `3`; set to `0` to disable retries.",
+			},
+			"retry_min_wait": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(defaultRetryMinWait / time.Second),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Minimum time in seconds to wait before retrying a request. The wait doubles with each attempt (with jitter) up to `retry_max_wait`. A `Retry-After` header returned by the API takes precedence. Defaults to `1`.",
+			},
+			"retry_max_wait": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(defaultRetryMaxWait / time.Second),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum time in seconds to wait between retries. Defaults to `30`.",
+			},
+			"requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "Maximum number of requests per second the provider sends, shared across all resources and data sources. Defaults to `0`, which only applies the limits advertised by Bitbucket in its `X-RateLimit-*` response headers.",
+			},
 		},
 		ConfigureFunc: providerConfigure,
 		ResourcesMap: map[string]*schema.Resource{
 			"bitbucket_branch_restriction":          resourceBranchRestriction(),
 			"bitbucket_branching_model":             resourceBranchingModel(),
 			"bitbucket_commit_file":                 resourceCommitFile(),
+			"bitbucket_commit_files":                resourceCommitFiles(),
+			"bitbucket_commit_report":               resourceCommitReport(),
+			"bitbucket_commit_report_annotations":   resourceCommitReportAnnotations(),
+			"bitbucket_commit_status":               resourceCommitStatus(),
 			"bitbucket_default_reviewers":           resourceDefaultReviewers(),
 			"bitbucket_deploy_key":                  resourceDeployKey(),
 			"bitbucket_deployment":                  resourceDeployment(),
 			"bitbucket_deployment_variable":         resourceDeploymentVariable(),
+			"bitbucket_deployment_variables":        resourceDeploymentVariables(),
 			"bitbucket_forked_repository":           resourceForkedRepository(),
 			"bitbucket_group":                       resourceGroup(),
 			"bitbucket_group_membership":            resourceGroupMembership(),
 			"bitbucket_hook":                        resourceHook(),
+			"bitbucket_issue":                       resourceIssue(),
+			"bitbucket_pipeline_cache_cleanup":      resourcePipelineCacheCleanup(),
+			"bitbucket_pipeline_run":                resourcePipelineRun(),
 			"bitbucket_pipeline_schedule":           resourcePipelineSchedule(),
 			"bitbucket_pipeline_ssh_key":            resourcePipelineSshKey(),
 			"bitbucket_pipeline_ssh_known_host":     resourcePipelineSshKnownHost(),
 			"bitbucket_pipeline_stop":               resourcePipelineStop(),
 			"bitbucket_project":                     resourceProject(),
+			"bitbucket_pull_request":                resourcePullRequest(),
 			"bitbucket_project_branching_model":     resourceProjectBranchingModel(),
 			"bitbucket_project_default_reviewers":   resourceProjectDefaultReviewers(),
 			"bitbucket_project_deploy_key":          resourceProjectDeployKey(),
 			"bitbucket_project_group_permission":    resourceProjectGroupPermission(),
 			"bitbucket_project_user_permission":     resourceProjectUserPermission(),
 			"bitbucket_repository":                  resourceRepository(),
+			"bitbucket_repository_download":         resourceRepositoryDownload(),
 			"bitbucket_repository_group_permission": resourceRepositoryGroupPermission(),
+			"bitbucket_repository_issue_export":     resourceRepositoryIssueExport(),
+			"bitbucket_repository_issue_import":     resourceRepositoryIssueImport(),
+			"bitbucket_repository_pipeline_config":  resourceRepositoryPipelineConfig(),
+			"bitbucket_repository_permissions":      resourceRepositoryPermissions(),
 			"bitbucket_repository_user_permission":  resourceRepositoryUserPermission(),
 			"bitbucket_repository_variable":         resourceRepositoryVariable(),
 			"bitbucket_ssh_key":                     resourceSshKey(),
@@ -106,6 +190,7 @@ func Provider() *schema.Provider {
 			"bitbucket_user_gpg_key":                resourceUserGpgKey(),
 			"bitbucket_workspace_hook":              resourceWorkspaceHook(),
 			"bitbucket_workspace_variable":          resourceWorkspaceVariable(),
+			"bitbucket_workspace_variables":         resourceWorkspaceVariables(),
 			"bitbucket_workspace_pipeline_runner":   resourceWorkspacePipelineRunner(),
 			"bitbucket_repository_pipeline_runner":  resourceRepositoryPipelineRunner(),
 		},
@@ -242,13 +327,41 @@ func Provider() *schema.Provider {
 			"bitbucket_workspace_gpg_public_key":              dataWorkspaceGpgPublicKey(),
 		},
 	}
+
+	addAccessTokenChecks(provider.ResourcesMap)
+	addTimeouts(provider.ResourcesMap)
+
+	return provider
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	authCtx := context.Background()
 
+	baseURL := d.Get("base_url").(string)
+	if baseURL == "" {
+		baseURL = BitbucketEndpoint
+	}
+
+	retryPolicy := RetryPolicy{
+		MaxRetries: d.Get("max_retries").(int),
+		MinWait:    time.Duration(d.Get("retry_min_wait").(int)) * time.Second,
+		MaxWait:    time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+	}
+	if retryPolicy.MinWait > retryPolicy.MaxWait {
+		return nil, fmt.Errorf("retry_min_wait (%s) must not be greater than retry_max_wait (%s)", retryPolicy.MinWait, retryPolicy.MaxWait)
+	}
+
+	// Both the HTTP client and the generated API client share one transport so
+	// that the retry policy and rate limiter apply to every request the
+	// provider makes.
+	limiter := newRateLimiter(d.Get("requests_per_second").(float64))
+	httpClient := &http.Client{
+		Transport: newRetryTransport(newRateLimitTransport(http.DefaultTransport, limiter), retryPolicy),
+	}
+
 	client := &Client{
-		HTTPClient: &http.Client{},
+		BaseURL:    baseURL,
+		HTTPClient: httpClient,
 	}
 
 	if username, ok := d.GetOk("username"); ok {
@@ -276,6 +389,24 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		authCtx = context.WithValue(authCtx, bitbucket.ContextAccessToken, token)
 	}
 
+	if v, ok := d.GetOk("access_token"); ok && v.(string) != "" {
+		log.Printf("[DEBUG] Using Access Token")
+		token := v.(string)
+		// The client authenticates with the bare token while inspecting it.
+		client.AccessToken = &AccessToken{Token: token}
+
+		// providerConfigure has no context of its own; bound the inspection
+		// so a hanging API does not stall the plan indefinitely.
+		ctx, cancel := context.WithTimeout(context.Background(), accessTokenDetectTimeout)
+		defer cancel()
+		accessToken, err := detectAccessToken(ctx, client, token)
+		if err != nil {
+			return nil, err
+		}
+		client.AccessToken = accessToken
+		authCtx = context.WithValue(authCtx, bitbucket.ContextAccessToken, token)
+	}
+
 	if clientID, ok := d.GetOk("oauth_client_id"); ok {
 		clientSecret, ok := d.GetOk("oauth_client_secret")
 		if !ok {
@@ -294,7 +425,26 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		authCtx = context.WithValue(authCtx, bitbucket.ContextOAuth2, tokenSource)
 	}
 
+	if v, ok := d.GetOk("auth_command"); ok {
+		var argv []string
+		for _, arg := range v.([]interface{}) {
+			s, _ := arg.(string)
+			argv = append(argv, s)
+		}
+		if len(argv) == 0 || argv[0] == "" {
+			return nil, fmt.Errorf("auth_command must name the program to run")
+		}
+		log.Printf("[DEBUG] Using auth_command %s", argv[0])
+
+		tokenSource := newCommandTokenSource(argv)
+
+		client.OAuthTokenSource = tokenSource
+		authCtx = context.WithValue(authCtx, bitbucket.ContextOAuth2, tokenSource)
+	}
+
 	conf := bitbucket.NewConfiguration()
+	conf.BasePath = apiBasePath(baseURL)
+	conf.HTTPClient = httpClient
 	apiClient := ProviderConfig{
 		ApiClient:   bitbucket.NewAPIClient(conf),
 		AuthContext: authCtx,
@@ -307,3 +457,10 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 
 	return clients, nil
 }
+
+// apiBasePath returns the base path used by the generated API client for the
+// given API root. The generated client expects the `2.0` version segment to be
+// part of the base path, whereas Client.Do includes it in every endpoint.
+func apiBasePath(baseURL string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/2.0"
+}
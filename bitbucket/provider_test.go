@@ -27,17 +27,37 @@ func TestProvider_impl(t *testing.T) {
 	var _ = Provider()
 }
 
+func TestProviderConfigureBaseURL(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"base_url": "http://localhost:8080/",
+	})
+
+	meta, err := providerConfigure(d)
+	if err != nil {
+		t.Fatalf("providerConfigure returned error: %v", err)
+	}
+	clients := meta.(Clients)
+
+	if got := clients.httpClient.baseURL(); got != "http://localhost:8080/" {
+		t.Errorf("http client base URL = %q, want %q", got, "http://localhost:8080/")
+	}
+	if got := apiBasePath("http://localhost:8080/"); got != "http://localhost:8080/2.0" {
+		t.Errorf("generated client base path = %q, want %q", got, "http://localhost:8080/2.0")
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 
-	// Allow either bitbucket u/p or oauth creds for testing
+	// Allow either bitbucket u/p, oauth creds or an access token for testing
 	user_v := os.Getenv("BITBUCKET_USERNAME")
 	pass_v := os.Getenv("BITBUCKET_PASSWORD")
 	oauth_token_v := os.Getenv("BITBUCKET_OAUTH_TOKEN")
 	oauth_client_v := os.Getenv("BITBUCKET_OAUTH_CLIENT_ID")
 	oauth_secret_v := os.Getenv("BITBUCKET_OAUTH_CLIENT_SECRET")
+	access_token_v := os.Getenv("BITBUCKET_ACCESS_TOKEN")
 
-	if user_v == "" && oauth_token_v == "" && oauth_client_v == "" {
-		t.Fatal("BITBUCKET_USERNAME or BITBUCKET_OAUTH_TOKEN or BITBUCKET_OAUTH_CLIENT_ID must be set for acceptance tests")
+	if user_v == "" && oauth_token_v == "" && oauth_client_v == "" && access_token_v == "" {
+		t.Fatal("BITBUCKET_USERNAME or BITBUCKET_OAUTH_TOKEN or BITBUCKET_OAUTH_CLIENT_ID or BITBUCKET_ACCESS_TOKEN must be set for acceptance tests")
 	}
 
 	if (pass_v == "" && user_v != "") || (pass_v != "" && (oauth_token_v != "" || oauth_secret_v != "")) {
@@ -0,0 +1,220 @@
+package bitbucket
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// nearLimitRate is the request rate the limiter falls back to when
+	// Bitbucket reports that the quota is nearly exhausted
+	// (X-RateLimit-NearLimit) without saying when it resets.
+	nearLimitRate = 1.0
+	// nearLimitWindow is how long the near-limit slowdown stays in effect.
+	nearLimitWindow = time.Minute
+	// nearLimitFraction is the share of X-RateLimit-Limit below which the
+	// remaining quota counts as nearly exhausted, the same threshold at
+	// which Bitbucket sets X-RateLimit-NearLimit.
+	nearLimitFraction = 0.2
+)
+
+// rateLimiter is a token bucket shared by every request the provider makes.
+// Besides the configured rate it adapts to the X-RateLimit-* headers returned
+// by Bitbucket: when the quota is nearly exhausted it spreads the remaining
+// requests over the time left until the reset, and when the quota is exhausted
+// (or a request is rate limited) it pauses all requests until the API allows
+// them again.
+type rateLimiter struct {
+	mu sync.Mutex
+
+	// rate is the configured number of requests per second; zero means the
+	// limiter only applies the limits advertised by the API.
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// adaptiveRate, when non-zero, caps the rate until adaptiveUntil.
+	adaptiveRate  float64
+	adaptiveUntil time.Time
+	// pausedUntil blocks every request until the given time.
+	pausedUntil time.Time
+
+	now func() time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	burst := math.Max(1, math.Ceil(requestsPerSecond))
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		now:    time.Now,
+	}
+}
+
+// currentRate returns the rate in effect at now, or zero when unlimited.
+func (l *rateLimiter) currentRate(now time.Time) float64 {
+	rate := l.rate
+	if l.adaptiveRate > 0 && now.Before(l.adaptiveUntil) {
+		if rate == 0 || l.adaptiveRate < rate {
+			rate = l.adaptiveRate
+		}
+	}
+	return rate
+}
+
+// reserve takes a token if one is available and otherwise returns how long
+// the caller should wait before trying again.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	rate := l.currentRate(now)
+	if rate == 0 {
+		l.last = now
+		return 0
+	}
+
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*rate)
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / rate * float64(time.Second))
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait := l.reserve()
+		if wait <= 0 {
+			return nil
+		}
+		log.Printf("[DEBUG] Client-side rate limit reached, waiting %s", wait)
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// observe adjusts the limiter to the rate limit state reported by a response.
+func (l *rateLimiter) observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait, ok := retryAfter(resp)
+		if !ok {
+			wait = defaultRetryMinWait
+		}
+		l.pause(now.Add(wait))
+		return
+	}
+
+	remaining, hasRemaining := headerInt(resp.Header, "X-RateLimit-Remaining")
+	reset, hasReset := rateLimitReset(resp.Header, now)
+
+	// The rate is only capped once the quota runs low; spreading a plentiful
+	// quota over the window would slow down requests for no reason.
+	nearLimit := strings.EqualFold(resp.Header.Get("X-RateLimit-NearLimit"), "true")
+	if limit, ok := headerInt(resp.Header, "X-RateLimit-Limit"); ok && hasRemaining && limit > 0 {
+		nearLimit = nearLimit || float64(remaining) < float64(limit)*nearLimitFraction
+	}
+
+	switch {
+	case hasRemaining && remaining <= 0 && hasReset:
+		l.pause(reset)
+	case nearLimit && hasRemaining && hasReset && reset.After(now):
+		l.adaptiveRate = float64(remaining) / reset.Sub(now).Seconds()
+		l.adaptiveUntil = reset
+	case nearLimit:
+		l.adaptiveRate = nearLimitRate
+		l.adaptiveUntil = now.Add(nearLimitWindow)
+	case hasRemaining:
+		l.adaptiveRate = 0
+	}
+}
+
+func (l *rateLimiter) pause(until time.Time) {
+	if until.After(l.pausedUntil) {
+		log.Printf("[DEBUG] Bitbucket rate limit exhausted, pausing requests until %s", until.Format(time.RFC3339))
+		l.pausedUntil = until
+	}
+}
+
+// headerInt parses an integer response header.
+func headerInt(h http.Header, name string) (int64, bool) {
+	v := h.Get(name)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// rateLimitReset returns when the current rate limit window resets. The
+// X-RateLimit-Reset header is accepted either as a Unix timestamp or as a
+// number of seconds from now.
+func rateLimitReset(h http.Header, now time.Time) (time.Time, bool) {
+	n, ok := headerInt(h, "X-RateLimit-Reset")
+	if !ok || n < 0 {
+		return time.Time{}, false
+	}
+	// Anything smaller than a day is a relative number of seconds.
+	if n < int64(24*time.Hour/time.Second) {
+		return now.Add(time.Duration(n) * time.Second), true
+	}
+	return time.Unix(n, 0), true
+}
+
+// rateLimitTransport is an http.RoundTripper that throttles requests through a
+// rateLimiter. It sits beneath the retry transport so retries are throttled too.
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func newRateLimitTransport(next http.RoundTripper, limiter *rateLimiter) *rateLimitTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &rateLimitTransport{next: next, limiter: limiter}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err == nil {
+		t.limiter.observe(resp)
+	}
+	return resp, err
+}
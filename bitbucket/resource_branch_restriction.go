@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"log"
 
-	"net/http"
 	"net/url"
 	"strings"
 
@@ -194,9 +193,9 @@ func resourceBranchRestrictionsCreate(ctx context.Context, d *schema.ResourceDat
 
 	repo := d.Get("repository").(string)
 	workspace := d.Get("owner").(string)
-	branchRestrictionReq, res, err := brApi.RepositoriesWorkspaceRepoSlugBranchRestrictionsPost(c.AuthContext, *branchRestriction, repo, workspace)
+	branchRestrictionReq, res, err := brApi.RepositoriesWorkspaceRepoSlugBranchRestrictionsPost(c.requestContext(ctx), *branchRestriction, repo, workspace)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.SetId(fmt.Sprintf("%v", branchRestrictionReq.Id))
@@ -208,17 +207,17 @@ func resourceBranchRestrictionsRead(ctx context.Context, d *schema.ResourceData,
 	c := m.(Clients).genClient
 	brApi := c.ApiClient.BranchRestrictionsApi
 
-	brRes, res, err := brApi.RepositoriesWorkspaceRepoSlugBranchRestrictionsIdGet(c.AuthContext, url.PathEscape(d.Id()),
+	brRes, res, err := brApi.RepositoriesWorkspaceRepoSlugBranchRestrictionsIdGet(c.requestContext(ctx), url.PathEscape(d.Id()),
 		d.Get("repository").(string), d.Get("owner").(string))
 
-	if res != nil && res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Branch Restrictions (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.SetId(fmt.Sprintf("%v", brRes.Id))
@@ -238,12 +237,12 @@ func resourceBranchRestrictionsUpdate(ctx context.Context, d *schema.ResourceDat
 	brApi := c.ApiClient.BranchRestrictionsApi
 	branchRestriction := createBranchRestriction(d)
 
-	_, res, err := brApi.RepositoriesWorkspaceRepoSlugBranchRestrictionsIdPut(c.AuthContext,
+	_, res, err := brApi.RepositoriesWorkspaceRepoSlugBranchRestrictionsIdPut(c.requestContext(ctx),
 		*branchRestriction, url.PathEscape(d.Id()),
 		d.Get("repository").(string), d.Get("owner").(string))
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourceBranchRestrictionsRead(ctx, d, m)
@@ -253,16 +252,16 @@ func resourceBranchRestrictionsDelete(ctx context.Context, d *schema.ResourceDat
 	c := m.(Clients).genClient
 	brApi := c.ApiClient.BranchRestrictionsApi
 
-	res, err := brApi.RepositoriesWorkspaceRepoSlugBranchRestrictionsIdDelete(c.AuthContext, url.PathEscape(d.Id()),
+	res, err := brApi.RepositoriesWorkspaceRepoSlugBranchRestrictionsIdDelete(c.requestContext(ctx), url.PathEscape(d.Id()),
 		d.Get("repository").(string), d.Get("owner").(string))
 
-	if res != nil && res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Branch Restrictions (%s) not found, removing from state", d.Id())
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return nil
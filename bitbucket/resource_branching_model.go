@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -158,13 +157,13 @@ func resourceBranchingModelsPut(ctx context.Context, d *schema.ResourceData, m i
 		return diag.FromErr(err)
 	}
 
-	branchingModelReq, err := client.Put(fmt.Sprintf("2.0/repositories/%s/%s/branching-model/settings",
+	branchingModelReq, err := client.PutContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/branching-model/settings",
 		d.Get("owner").(string),
 		d.Get("repository").(string),
 	), bytes.NewBuffer(bytedata))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(branchingModelReq.Body)
@@ -189,14 +188,18 @@ func resourceBranchingModelsRead(ctx context.Context, d *schema.ResourceData, m
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	branchingModelsReq, _ := client.Get(fmt.Sprintf("2.0/repositories/%s/%s/branching-model/settings", owner, repo))
+	branchingModelsReq, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/branching-model/settings", owner, repo))
 
-	if branchingModelsReq.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Branching Model (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	if branchingModelsReq.Body == nil {
 		return diag.Errorf("error getting Branching Model (%s): empty response", d.Id())
 	}
@@ -242,10 +245,10 @@ func resourceBranchingModelsDelete(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Put(fmt.Sprintf("2.0/repositories/%s/%s/branching-model/settings", owner, repo), nil)
+	_, err = client.PutContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/branching-model/settings", owner, repo), nil)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return diag.FromErr(err)
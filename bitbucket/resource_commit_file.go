@@ -1,25 +1,33 @@
 package bitbucket
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"mime/multipart"
-	"net/http"
+	"log"
 	"strings"
 
-	"github.com/DrFaust92/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceCommitFile() *schema.Resource {
 	return &schema.Resource{
-		CreateWithoutTimeout: resourceCommitFilePut,
+		CreateWithoutTimeout: resourceCommitFileCreate,
 		ReadWithoutTimeout:   resourceCommitFileRead,
+		UpdateWithoutTimeout: resourceCommitFileUpdate,
 		DeleteWithoutTimeout: resourceCommitFileDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				workspace, repoSlug, branch, filename, err := commitFileId(d.Id())
+				if err != nil {
+					return nil, err
+				}
+				d.Set("workspace", workspace)
+				d.Set("repo_slug", repoSlug)
+				d.Set("branch", branch)
+				d.Set("filename", filename)
+				return []*schema.ResourceData{d}, nil
+			},
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -36,7 +44,6 @@ func resourceCommitFile() *schema.Resource {
 			"content": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 			"filename": {
 				Type:     schema.TypeString,
@@ -51,14 +58,18 @@ func resourceCommitFile() *schema.Resource {
 			"commit_message": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "The SHA of the commit that modified the file",
-				ForceNew:    true,
+				Description: "The message of the commits that write the file",
 			},
 			"commit_author": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "The SHA of the commit that modified the file",
-				ForceNew:    true,
+				Description: "The author of the commits that write the file",
+			},
+			"delete_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether destroying the resource deletes the file with a commit",
 			},
 			"commit_sha": {
 				Type:        schema.TypeString,
@@ -69,92 +80,119 @@ func resourceCommitFile() *schema.Resource {
 	}
 }
 
-func resourceCommitFilePut(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func commitFileId(id string) (workspace, repoSlug, branch, filename string, err error) {
+	// The file name is last and may itself contain slashes.
+	parts := strings.SplitN(id, "/", 4)
+	if len(parts) != 4 || parts[0] == "" || parts[1] == "" || parts[2] == "" || parts[3] == "" {
+		return "", "", "", "", fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/BRANCH/FILENAME", id)
+	}
+	return parts[0], parts[1], parts[2], parts[3], nil
+}
+
+func expandCommitFileCommit(d *schema.ResourceData) srcCommit {
+	return srcCommit{
+		Branch:  d.Get("branch").(string),
+		Message: d.Get("commit_message").(string),
+		Author:  d.Get("commit_author").(string),
+		Files:   map[string]string{},
+	}
+}
+
+func resourceCommitFileCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(Clients).httpClient
 
 	repoSlug := d.Get("repo_slug").(string)
 	workspace := d.Get("workspace").(string)
-	content := d.Get("content").(string)
 	filename := d.Get("filename").(string)
 	branch := d.Get("branch").(string)
-	commitMessage := d.Get("commit_message").(string)
-	commitAuthor := d.Get("commit_author").(string)
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, _ := writer.CreateFormFile(filename, filename)
-	_, err := part.Write([]byte(content))
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer writer.Close()
+	commit := expandCommitFileCommit(d)
+	commit.Files[filename] = d.Get("content").(string)
 
-	messageFormField, err := writer.CreateFormField("message")
+	sha, err := postSrcCommit(ctx, &client, workspace, repoSlug, commit)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
-	_, err = messageFormField.Write([]byte(commitMessage))
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	authorFormField, err := writer.CreateFormField("author")
-	if err != nil {
-		return diag.FromErr(err)
+
+	d.SetId(fmt.Sprintf("%s/%s/%s/%s", workspace, repoSlug, branch, filename))
+	d.Set("commit_sha", sha)
+
+	return resourceCommitFileRead(ctx, d, m)
+}
+
+func resourceCommitFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	repoSlug := d.Get("repo_slug").(string)
+	workspace := d.Get("workspace").(string)
+	filename := d.Get("filename").(string)
+	branch := d.Get("branch").(string)
+
+	// The file is compared at the head of the branch, so edits made since
+	// the last commit of the resource show up as drift.
+	head, err := getBranchHead(ctx, &client, workspace, repoSlug, branch)
+	if IsNotFound(err) {
+		log.Printf("[WARN] Commit File (%s) branch not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
 	}
-	_, err = authorFormField.Write([]byte(commitAuthor))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	branchFormField, err := writer.CreateFormField("branch")
-	if err != nil {
-		return diag.FromErr(err)
+	content, err := getSrcFile(ctx, &client, workspace, repoSlug, head, filename)
+	if IsNotFound(err) {
+		log.Printf("[WARN] Commit File (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
 	}
-	_, err = branchFormField.Write([]byte(branch))
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
-	res, err := client.PostWithContentType(fmt.Sprintf("2.0/repositories/%s/%s/src",
-		workspace,
-		repoSlug,
-	), writer.FormDataContentType(), body)
-
-	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+	d.Set("content", content)
+	if d.Get("commit_sha").(string) == "" {
+		d.Set("commit_sha", head)
 	}
+	// State written before delete_on_destroy existed has no value for it,
+	// which would otherwise show up as a change to the default.
+	d.Set("delete_on_destroy", d.Get("delete_on_destroy").(bool))
 
-	if res.StatusCode != http.StatusCreated {
-		return diag.FromErr(fmt.Errorf(""))
-	}
+	return nil
+}
 
-	d.SetId(fmt.Sprintf("%s/%s/%s/%s", workspace, repoSlug, branch, filename))
+func resourceCommitFileUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	if d.HasChange("content") {
+		commit := expandCommitFileCommit(d)
+		commit.Files[d.Get("filename").(string)] = d.Get("content").(string)
 
-	location, _ := res.Location()
-	splitPath := strings.Split(location.Path, "/")
-	d.Set("commit_sha", splitPath[len(splitPath)-1])
+		sha, err := postSrcCommit(ctx, &client, d.Get("workspace").(string), d.Get("repo_slug").(string), commit)
+		if err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+		d.Set("commit_sha", sha)
+	}
 
 	return resourceCommitFileRead(ctx, d, m)
 }
 
-func resourceCommitFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(Clients).genClient
-	sourceApi := c.ApiClient.SourceApi
+func resourceCommitFileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.Get("delete_on_destroy").(bool) {
+		log.Printf("[DEBUG] Leaving %s in place", d.Id())
+		return nil
+	}
 
-	repoSlug := d.Get("repo_slug").(string)
-	workspace := d.Get("workspace").(string)
-	filename := d.Get("filename").(string)
-	commit := d.Get("commit_sha").(string)
+	client := m.(Clients).httpClient
 
-	_, res, err := sourceApi.RepositoriesWorkspaceRepoSlugSrcCommitPathGet(c.AuthContext, commit, filename, repoSlug, workspace, &bitbucket.SourceApiRepositoriesWorkspaceRepoSlugSrcCommitPathGetOpts{})
+	commit := expandCommitFileCommit(d)
+	commit.Deletions = []string{d.Get("filename").(string)}
 
-	if err := handleClientError(res, err); err != nil {
+	_, err := postSrcCommit(ctx, &client, d.Get("workspace").(string), d.Get("repo_slug").(string), commit)
+	if err != nil && !IsNotFound(err) {
 		return diag.FromErr(err)
 	}
 
 	return nil
 }
-
-func resourceCommitFileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	return nil
-}
@@ -1,12 +1,14 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func testAccBitbucketCommitFileConfig(owner, rName string) string {
@@ -50,3 +52,177 @@ func TestAccBitbucketCommitFile_basic(t *testing.T) {
 		},
 	})
 }
+
+func TestAccBitbucketCommitFile_update(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	owner := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_commit_file.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitFileUpdateConfig(owner, rName, "v1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "content", "v1"),
+					resource.TestCheckResourceAttrSet(resourceName, "commit_sha"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"commit_author", "commit_message", "delete_on_destroy"},
+			},
+			{
+				// The new content is committed without replacing the resource.
+				Config: testAccBitbucketCommitFileUpdateConfig(owner, rName, "v2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "content", "v2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketCommitFileUpdateConfig(owner, rName, content string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+}
+
+resource "bitbucket_commit_file" "test" {
+  workspace         = bitbucket_repository.test.owner
+  repo_slug         = bitbucket_repository.test.name
+  branch            = "main"
+  filename          = "README.md"
+  content           = %[3]q
+  commit_author     = "Unit test <unit@test.local>"
+  commit_message    = "Set README to %[3]s"
+  delete_on_destroy = true
+}
+`, owner, rName, content)
+}
+
+func TestCommitFileID(t *testing.T) {
+	workspace, repo, branch, filename, err := commitFileId("acme/platform/main/docs/README.md")
+	if err != nil || workspace != "acme" || repo != "platform" || branch != "main" || filename != "docs/README.md" {
+		t.Errorf("commitFileId = %q, %q, %q, %q, %v", workspace, repo, branch, filename, err)
+	}
+	if _, _, _, _, err := commitFileId("acme/platform/main"); err == nil {
+		t.Error("expected an error for an ID without a file name")
+	}
+}
+
+func TestCommitFileDriftUpdatesInPlace(t *testing.T) {
+	server := &fakeSrcServer{t: t, files: map[string]string{}}
+	client, _ := newMockServerClient(t, server.handler)
+	meta := Clients{httpClient: *client}
+	r := resourceCommitFile()
+	ctx := context.Background()
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"workspace":         "acme",
+		"repo_slug":         "platform",
+		"branch":            "main",
+		"filename":          "docs/README.md",
+		"content":           "v1",
+		"commit_message":    "Update README",
+		"commit_author":     "Bot <bot@example.com>",
+		"delete_on_destroy": true,
+	})
+
+	diff, err := r.Diff(ctx, nil, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, diags := r.Apply(ctx, nil, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if server.files["docs/README.md"] != "v1" || state.Attributes["commit_sha"] != "c1" {
+		t.Fatalf("file not committed: files=%v state=%v", server.files, state.Attributes)
+	}
+
+	// An edit made in the UI is detected and reverted by a new commit
+	// without replacing the resource.
+	server.files["docs/README.md"] = "edited"
+	server.head++
+	state, diags = r.RefreshWithoutUpgrade(ctx, state, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if state.Attributes["content"] != "edited" {
+		t.Fatalf("drift not detected: %v", state.Attributes)
+	}
+	diff, err = r.Diff(ctx, state, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || diff.RequiresNew() {
+		t.Fatalf("expected an in-place update, got %v", diff)
+	}
+	state, diags = r.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if server.files["docs/README.md"] != "v1" || state.Attributes["commit_sha"] != "c3" {
+		t.Errorf("drift not corrected: files=%v state=%v", server.files, state.Attributes)
+	}
+
+	destroy := &terraform.InstanceDiff{Destroy: true}
+	if _, diags := r.Apply(ctx, state, destroy, meta); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if _, ok := server.files["docs/README.md"]; ok {
+		t.Error("file was not deleted on destroy")
+	}
+}
+
+func TestCommitFileUpgradeKeepsFileOnDestroy(t *testing.T) {
+	server := &fakeSrcServer{t: t, head: 1, files: map[string]string{"docs/README.md": "v1"}}
+	client, _ := newMockServerClient(t, server.handler)
+	meta := Clients{httpClient: *client}
+	r := resourceCommitFile()
+	ctx := context.Background()
+
+	// State written by an earlier version of the provider.
+	state := &terraform.InstanceState{
+		ID: "acme/platform/main/docs/README.md",
+		Attributes: map[string]string{
+			"id":             "acme/platform/main/docs/README.md",
+			"workspace":      "acme",
+			"repo_slug":      "platform",
+			"branch":         "main",
+			"filename":       "docs/README.md",
+			"content":        "v1",
+			"commit_message": "Update README",
+			"commit_author":  "Bot <bot@example.com>",
+		},
+	}
+	state, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if state.Attributes["delete_on_destroy"] != "false" {
+		t.Errorf("delete_on_destroy = %q, want false", state.Attributes["delete_on_destroy"])
+	}
+
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"workspace":      "acme",
+		"repo_slug":      "platform",
+		"branch":         "main",
+		"filename":       "docs/README.md",
+		"content":        "v1",
+		"commit_message": "Update README",
+		"commit_author":  "Bot <bot@example.com>",
+	}), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Errorf("unexpected diff after upgrade: %v", diff)
+	}
+}
@@ -0,0 +1,449 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCommitFiles() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceCommitFilesCreate,
+		ReadWithoutTimeout:   resourceCommitFilesRead,
+		UpdateWithoutTimeout: resourceCommitFilesUpdate,
+		DeleteWithoutTimeout: resourceCommitFilesDelete,
+		CustomizeDiff:        validateCommitFilesPaths,
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"branch": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Branch the commits are made on. It is created if it does not exist",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"files": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Description:  "Content of the managed files, keyed by path",
+				AtLeastOneOf: []string{"files", "deletions"},
+				ValidateFunc: validateCommitFilesNames,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"deletions": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Description:  "Paths of files that must not exist on the branch",
+				AtLeastOneOf: []string{"files", "deletions"},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+			"commit_message": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Message of the commits made by the resource",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"commit_author": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Author of the commits, in the form `Name <email>`. Defaults to the authenticated user",
+			},
+			"parents": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Commits the first commit must be based on. When set, later commits must be based on `commit_sha`. A commit fails if the branch has moved on",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"delete_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether destroying the resource deletes the files in `files` with a commit",
+			},
+			"commit_sha": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA of the last commit made by the resource",
+			},
+			"file_sha256": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "SHA-256 checksums of the files at the head of the branch, keyed by path",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// srcCommitFields are the form fields of a /src commit other than files. A
+// file with one of these paths would be read as the field instead.
+var srcCommitFields = []string{"branch", "message", "author", "parents", "files"}
+
+// srcCommit is a commit made through the /src endpoint. Files maps paths to
+// their new content; Deletions lists paths to remove.
+type srcCommit struct {
+	Branch    string
+	Message   string
+	Author    string
+	Parents   []string
+	Files     map[string]string
+	Deletions []string
+}
+
+// postSrcCommit makes commit in a single request and returns the SHA of the
+// new commit.
+func postSrcCommit(ctx context.Context, client *Client, workspace, repoSlug string, commit srcCommit) (string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	fields := map[string]string{
+		"branch":  commit.Branch,
+		"message": commit.Message,
+		"author":  commit.Author,
+		"parents": strings.Join(commit.Parents, ","),
+	}
+	for _, name := range []string{"branch", "message", "author", "parents"} {
+		if fields[name] == "" {
+			continue
+		}
+		if err := writer.WriteField(name, fields[name]); err != nil {
+			return "", err
+		}
+	}
+
+	paths := make([]string, 0, len(commit.Files))
+	for p := range commit.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		part, err := writer.CreateFormFile(p, path.Base(p))
+		if err != nil {
+			return "", err
+		}
+		if _, err := part.Write([]byte(commit.Files[p])); err != nil {
+			return "", err
+		}
+	}
+
+	// A path named in a "files" field without content is deleted.
+	for _, p := range commit.Deletions {
+		if err := writer.WriteField("files", p); err != nil {
+			return "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	res, err := client.PostWithContentTypeContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/src", workspace, repoSlug), writer.FormDataContentType(), body)
+	if err != nil {
+		return "", err
+	}
+	res.Body.Close()
+
+	location, err := res.Location()
+	if err != nil {
+		return "", fmt.Errorf("commit to %s/%s was created without a location: %w", workspace, repoSlug, err)
+	}
+	return path.Base(location.Path), nil
+}
+
+// getBranchHead returns the SHA of the commit at the head of branch.
+func getBranchHead(ctx context.Context, client *Client, workspace, repoSlug, branch string) (string, error) {
+	res, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/refs/branches/%s", workspace, repoSlug, url.PathEscape(branch)))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var b Branch
+	if err := json.Unmarshal(body, &b); err != nil {
+		return "", err
+	}
+	return b.Target.Hash, nil
+}
+
+// getSrcFile returns the raw content of the file at filePath in commit.
+func getSrcFile(ctx context.Context, client *Client, workspace, repoSlug, commit, filePath string) (string, error) {
+	segments := strings.Split(strings.TrimPrefix(filePath, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	res, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/src/%s/%s", workspace, repoSlug, commit, strings.Join(segments, "/")))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func contentSHA256(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func validateCommitFilesNames(val interface{}, key string) (warns []string, errs []error) {
+	for p := range val.(map[string]interface{}) {
+		for _, field := range srcCommitFields {
+			if p == field {
+				errs = append(errs, fmt.Errorf("%s: %q cannot be committed, Bitbucket reads it as the %s field of the commit", key, p, field))
+			}
+		}
+	}
+	return warns, errs
+}
+
+func validateCommitFilesPaths(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	files := d.Get("files").(map[string]interface{})
+	for _, p := range d.Get("deletions").(*schema.Set).List() {
+		if _, ok := files[p.(string)]; ok {
+			return fmt.Errorf("%q is listed in both files and deletions", p.(string))
+		}
+	}
+	return nil
+}
+
+func expandCommitFilesContent(files map[string]interface{}) map[string]string {
+	out := make(map[string]string, len(files))
+	for p, content := range files {
+		out[p] = content.(string)
+	}
+	return out
+}
+
+func expandCommitFilesCommit(d *schema.ResourceData) srcCommit {
+	return srcCommit{
+		Branch:  d.Get("branch").(string),
+		Message: d.Get("commit_message").(string),
+		Author:  d.Get("commit_author").(string),
+		Files:   map[string]string{},
+	}
+}
+
+func resourceCommitFilesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+
+	commit := expandCommitFilesCommit(d)
+	for _, parent := range d.Get("parents").([]interface{}) {
+		commit.Parents = append(commit.Parents, parent.(string))
+	}
+	commit.Files = expandCommitFilesContent(d.Get("files").(map[string]interface{}))
+	for _, p := range d.Get("deletions").(*schema.Set).List() {
+		commit.Deletions = append(commit.Deletions, p.(string))
+	}
+	sort.Strings(commit.Deletions)
+
+	sha, err := postSrcCommit(ctx, &client, workspace, repoSlug, commit)
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", workspace, repoSlug, commit.Branch))
+	d.Set("commit_sha", sha)
+	log.Printf("[DEBUG] Committed %d files and %d deletions to %s as %s", len(commit.Files), len(commit.Deletions), d.Id(), sha)
+
+	return resourceCommitFilesRead(ctx, d, m)
+}
+
+func resourceCommitFilesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	branch := d.Get("branch").(string)
+
+	head, err := getBranchHead(ctx, &client, workspace, repoSlug, branch)
+	if IsNotFound(err) {
+		log.Printf("[WARN] Commit Files (%s) branch not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Files that changed or disappeared at the head of the branch, and
+	// deleted files that came back, are dropped from or updated in state so
+	// that the next plan restores them.
+	files := map[string]interface{}{}
+	hashes := map[string]interface{}{}
+	for p, content := range d.Get("files").(map[string]interface{}) {
+		current, err := getSrcFile(ctx, &client, workspace, repoSlug, head, p)
+		if IsNotFound(err) {
+			log.Printf("[DEBUG] File %s no longer exists on %s", p, d.Id())
+			continue
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		hash := contentSHA256(current)
+		hashes[p] = hash
+		if hash == contentSHA256(content.(string)) {
+			files[p] = content
+		} else {
+			log.Printf("[DEBUG] File %s was changed on %s", p, d.Id())
+			files[p] = current
+		}
+	}
+
+	deletions := make([]interface{}, 0)
+	for _, p := range d.Get("deletions").(*schema.Set).List() {
+		_, err := getSrcFile(ctx, &client, workspace, repoSlug, head, p.(string))
+		if IsNotFound(err) {
+			deletions = append(deletions, p)
+			continue
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		log.Printf("[DEBUG] Deleted file %s exists again on %s", p, d.Id())
+	}
+
+	d.Set("files", files)
+	d.Set("deletions", deletions)
+	d.Set("file_sha256", hashes)
+
+	return nil
+}
+
+func resourceCommitFilesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+
+	if !d.HasChanges("files", "deletions") {
+		return resourceCommitFilesRead(ctx, d, m)
+	}
+
+	commit := expandCommitFilesCommit(d)
+	// The configured parents only apply to the first commit; later commits
+	// must be based on the last one the resource made.
+	if len(d.Get("parents").([]interface{})) > 0 {
+		commit.Parents = []string{d.Get("commit_sha").(string)}
+	}
+
+	o, n := d.GetChange("files")
+	oldFiles := expandCommitFilesContent(o.(map[string]interface{}))
+	newFiles := expandCommitFilesContent(n.(map[string]interface{}))
+	for p, content := range newFiles {
+		if old, ok := oldFiles[p]; !ok || old != content {
+			commit.Files[p] = content
+		}
+	}
+
+	// Files no longer managed by the resource are deleted, as are new
+	// deletions.
+	deletions := map[string]bool{}
+	for p := range oldFiles {
+		if _, ok := newFiles[p]; !ok {
+			deletions[p] = true
+		}
+	}
+	for _, p := range d.Get("deletions").(*schema.Set).List() {
+		deletions[p.(string)] = true
+	}
+	oldDeletions, _ := d.GetChange("deletions")
+	for _, p := range oldDeletions.(*schema.Set).List() {
+		if d.Get("deletions").(*schema.Set).Contains(p) {
+			// Only deletions that are not already in effect need a commit.
+			delete(deletions, p.(string))
+		}
+	}
+	for p := range deletions {
+		commit.Deletions = append(commit.Deletions, p)
+	}
+	sort.Strings(commit.Deletions)
+
+	if len(commit.Files) == 0 && len(commit.Deletions) == 0 {
+		return resourceCommitFilesRead(ctx, d, m)
+	}
+
+	sha, err := postSrcCommit(ctx, &client, workspace, repoSlug, commit)
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+	d.Set("commit_sha", sha)
+	log.Printf("[DEBUG] Committed %d files and %d deletions to %s as %s", len(commit.Files), len(commit.Deletions), d.Id(), sha)
+
+	return resourceCommitFilesRead(ctx, d, m)
+}
+
+func resourceCommitFilesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.Get("delete_on_destroy").(bool) {
+		log.Printf("[DEBUG] Leaving the files of %s in place", d.Id())
+		return nil
+	}
+
+	client := m.(Clients).httpClient
+
+	commit := expandCommitFilesCommit(d)
+	for p := range d.Get("files").(map[string]interface{}) {
+		commit.Deletions = append(commit.Deletions, p)
+	}
+	if len(commit.Deletions) == 0 {
+		return nil
+	}
+	sort.Strings(commit.Deletions)
+
+	_, err := postSrcCommit(ctx, &client, d.Get("workspace").(string), d.Get("repo_slug").(string), commit)
+	if err != nil && !IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,287 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketCommitFiles_basic(t *testing.T) {
+	resourceName := "bitbucket_commit_files.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitFilesConfig(workspace, rName, "v1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "files.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, "file_sha256.README.md", contentSHA256("v1")),
+					resource.TestCheckResourceAttrSet(resourceName, "commit_sha"),
+				),
+			},
+			{
+				Config: testAccBitbucketCommitFilesConfig(workspace, rName, "v2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "file_sha256.README.md", contentSHA256("v2")),
+				),
+			},
+			{
+				Config: testAccBitbucketCommitFilesConfig(workspace, rName, "v3"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "file_sha256.README.md", contentSHA256("v3")),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketCommitFilesConfig(workspace, rName, version string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+}
+
+resource "bitbucket_commit_files" "test" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.name
+  branch         = "main"
+  commit_message = "Update files to %[3]s"
+  commit_author  = "Unit test <unit@test.local>"
+
+  files = {
+    "README.md"       = %[3]q
+    "config/app.yaml" = "version: %[3]s"
+  }
+
+  deletions = ["obsolete.txt"]
+}
+`, workspace, rName, version)
+}
+
+// fakeSrcServer is an in-memory branch that supports /src commits and file
+// reads.
+type fakeSrcServer struct {
+	t       *testing.T
+	files   map[string]string
+	head    int
+	commits []*http.Request
+}
+
+func (s *fakeSrcServer) handler(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/2.0/repositories/acme/platform/src":
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			s.t.Fatalf("invalid commit: %v", err)
+		}
+		if parents := r.FormValue("parents"); parents != "" && parents != fmt.Sprintf("c%d", s.head) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"type":"error","error":{"message":"parents do not match the branch head"}}`))
+			return
+		}
+		for name, headers := range r.MultipartForm.File {
+			f, _ := headers[0].Open()
+			content, _ := io.ReadAll(f)
+			s.files[name] = string(content)
+		}
+		for _, p := range r.MultipartForm.Value["files"] {
+			delete(s.files, p)
+		}
+		s.commits = append(s.commits, r)
+		s.head++
+		w.Header().Set("Location", fmt.Sprintf("https://api.bitbucket.org/2.0/repositories/acme/platform/commit/c%d", s.head))
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet && r.URL.Path == "/2.0/repositories/acme/platform/refs/branches/main":
+		fmt.Fprintf(w, `{"name":"main","target":{"hash":"c%d"}}`, s.head)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, fmt.Sprintf("/2.0/repositories/acme/platform/src/c%d/", s.head)):
+		content, ok := s.files[strings.TrimPrefix(r.URL.Path, fmt.Sprintf("/2.0/repositories/acme/platform/src/c%d/", s.head))]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type":"error","error":{"message":"No such file or directory"}}`))
+			return
+		}
+		w.Write([]byte(content))
+	default:
+		s.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	}
+}
+
+func TestCommitFilesLifecycle(t *testing.T) {
+	server := &fakeSrcServer{t: t, files: map[string]string{"old.txt": "stale", "keep.txt": "untouched"}}
+	client, _ := newMockServerClient(t, server.handler)
+	meta := Clients{httpClient: *client}
+	r := resourceCommitFiles()
+	ctx := context.Background()
+
+	apply := func(state *terraform.InstanceState, raw map[string]interface{}) (*terraform.InstanceState, diag.Diagnostics) {
+		t.Helper()
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.Apply(ctx, state, diff, meta)
+	}
+	config := map[string]interface{}{
+		"workspace":      "acme",
+		"repo_slug":      "platform",
+		"branch":         "main",
+		"commit_message": "Seed repository",
+		"files": map[string]interface{}{
+			"README.md":      "# Platform",
+			"ci/pipeline.sh": "#!/bin/sh\nmake\n",
+		},
+		"deletions": []interface{}{"old.txt"},
+		"parents":   []interface{}{"c0"},
+	}
+
+	state, diags := apply(nil, config)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if len(server.commits) != 1 {
+		t.Fatalf("made %d commits, want 1", len(server.commits))
+	}
+	want := map[string]string{"README.md": "# Platform", "ci/pipeline.sh": "#!/bin/sh\nmake\n", "keep.txt": "untouched"}
+	if !reflect.DeepEqual(server.files, want) {
+		t.Errorf("files after create = %v, want %v", server.files, want)
+	}
+	if state.ID != "acme/platform/main" || state.Attributes["commit_sha"] != "c1" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+	if state.Attributes["file_sha256.README.md"] != contentSHA256("# Platform") {
+		t.Errorf("file_sha256 = %v", state.Attributes)
+	}
+
+	// A file changed outside Terraform and a deleted file that came back are
+	// restored by a single commit; untouched files are not sent. The branch
+	// has moved on, so the commit is only made without parents.
+	server.files["README.md"] = "edited"
+	server.files["old.txt"] = "back"
+	server.head++
+	state, diags = r.RefreshWithoutUpgrade(ctx, state, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if state.Attributes["files.README.md"] != "edited" || state.Attributes["deletions.#"] != "0" {
+		t.Errorf("drift not detected: %v", state.Attributes)
+	}
+
+	if _, diags = apply(state, config); !diags.HasError() {
+		t.Fatal("expected a commit on a branch that moved on to be rejected")
+	}
+	delete(config, "parents")
+	state, diags = apply(state, config)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	last := server.commits[len(server.commits)-1]
+	if _, ok := last.MultipartForm.File["ci/pipeline.sh"]; ok {
+		t.Error("unchanged file was committed again")
+	}
+	if last.FormValue("parents") != "" {
+		t.Errorf("commit was based on %q", last.FormValue("parents"))
+	}
+	if server.files["README.md"] != "# Platform" || server.files["old.txt"] != "" {
+		t.Errorf("drift not corrected: %v", server.files)
+	}
+
+	// Files dropped from the configuration are deleted.
+	config["files"] = map[string]interface{}{"README.md": "# Platform"}
+	if _, diags = apply(state, config); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if _, ok := server.files["ci/pipeline.sh"]; ok {
+		t.Errorf("unmanaged file was not deleted: %v", server.files)
+	}
+}
+
+func TestCommitFilesUpdatesAreBasedOnLastCommit(t *testing.T) {
+	server := &fakeSrcServer{t: t, files: map[string]string{}}
+	client, _ := newMockServerClient(t, server.handler)
+	meta := Clients{httpClient: *client}
+	r := resourceCommitFiles()
+	ctx := context.Background()
+
+	apply := func(state *terraform.InstanceState, content string) (*terraform.InstanceState, diag.Diagnostics) {
+		t.Helper()
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"workspace":      "acme",
+			"repo_slug":      "platform",
+			"branch":         "main",
+			"commit_message": "Update config",
+			"files":          map[string]interface{}{"config.yml": content},
+			"parents":        []interface{}{"c0"},
+		}), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.Apply(ctx, state, diff, meta)
+	}
+
+	state, diags := apply(nil, "v1")
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	// The configured parents only apply to the first commit, so two updates
+	// in a row both succeed.
+	for i, content := range []string{"v2", "v3"} {
+		state, diags = apply(state, content)
+		if diags.HasError() {
+			t.Fatalf("update %d: unexpected diagnostics: %v", i+1, diags)
+		}
+		last := server.commits[len(server.commits)-1]
+		if want := fmt.Sprintf("c%d", i+1); last.FormValue("parents") != want {
+			t.Errorf("update %d was based on %q, want %q", i+1, last.FormValue("parents"), want)
+		}
+	}
+	if server.files["config.yml"] != "v3" || state.Attributes["commit_sha"] != "c3" {
+		t.Errorf("files %v, state %v", server.files, state.Attributes)
+	}
+
+	// A commit made by someone else in the meantime is not overwritten.
+	server.head++
+	if _, diags = apply(state, "v4"); !diags.HasError() {
+		t.Error("expected the update to be rejected after the branch moved on")
+	}
+}
+
+func TestCommitFilesRejectsFormFieldPaths(t *testing.T) {
+	for _, p := range []string{"branch", "message", "author", "parents", "files"} {
+		diags := resourceCommitFiles().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+			"workspace":      "acme",
+			"repo_slug":      "platform",
+			"branch":         "main",
+			"commit_message": "Seed repository",
+			"files":          map[string]interface{}{p: "content", "README.md": "# Platform"},
+		}))
+		if !diags.HasError() {
+			t.Errorf("%s: expected the path to be rejected", p)
+		}
+	}
+
+	diags := resourceCommitFiles().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"workspace":      "acme",
+		"repo_slug":      "platform",
+		"branch":         "main",
+		"commit_message": "Seed repository",
+		"files":          map[string]interface{}{"docs/branch": "content"},
+	}))
+	if diags.HasError() {
+		t.Errorf("unexpected diagnostics: %v", diags)
+	}
+}
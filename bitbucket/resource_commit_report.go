@@ -0,0 +1,440 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCommitReport() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceCommitReportPut,
+		ReadWithoutTimeout:   resourceCommitReportRead,
+		UpdateWithoutTimeout: resourceCommitReportPut,
+		DeleteWithoutTimeout: resourceCommitReportDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: validateCommitReportData,
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"commit": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Commit hash",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"report_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "External ID of the report, unique per commit",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"title": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"details": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"report_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"SECURITY",
+					"COVERAGE",
+					"TEST",
+					"BUG",
+				}, false),
+			},
+			"result": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"PASSED",
+					"FAILED",
+					"PENDING",
+				}, false),
+			},
+			"reporter": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"link": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+			},
+			"logo_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+			},
+			"remote_link_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"data": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 10,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"title": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Required: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"BOOLEAN",
+								"DATE",
+								"DURATION",
+								"LINK",
+								"NUMBER",
+								"PERCENTAGE",
+								"TEXT",
+							}, false),
+						},
+						"value": {
+							Type:             schema.TypeString,
+							Required:         true,
+							Description:      "Value of the field, converted according to `type`",
+							DiffSuppressFunc: suppressEquivalentReportDataValue,
+						},
+						"link_text": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "Text shown for a LINK field. Defaults to the URL",
+						},
+					},
+				},
+			},
+			"uuid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_on": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"updated_on": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// CodeInsightsReport is a Code Insights report as sent to and returned by the
+// reports API.
+type CodeInsightsReport struct {
+	UUID              string                   `json:"uuid,omitempty"`
+	Title             string                   `json:"title"`
+	Details           string                   `json:"details"`
+	ExternalID        string                   `json:"external_id,omitempty"`
+	Reporter          string                   `json:"reporter,omitempty"`
+	Link              string                   `json:"link,omitempty"`
+	LogoURL           string                   `json:"logo_url,omitempty"`
+	RemoteLinkEnabled bool                     `json:"remote_link_enabled"`
+	ReportType        string                   `json:"report_type"`
+	Result            string                   `json:"result,omitempty"`
+	Data              []CodeInsightsReportData `json:"data"`
+	CreatedOn         string                   `json:"created_on,omitempty"`
+	UpdatedOn         string                   `json:"updated_on,omitempty"`
+}
+
+// CodeInsightsReportData is a typed data field displayed with a report.
+type CodeInsightsReportData struct {
+	Type  string          `json:"type,omitempty"`
+	Title string          `json:"title"`
+	Value json.RawMessage `json:"value"`
+}
+
+// CodeInsightsReportLink is the value of a LINK data field.
+type CodeInsightsReportLink struct {
+	Text string `json:"text"`
+	Href string `json:"href"`
+}
+
+func commitReportId(id string) (workspace, repoSlug, commit, reportID string, err error) {
+	parts := strings.SplitN(id, "/", 4)
+	if len(parts) != 4 || parts[0] == "" || parts[1] == "" || parts[2] == "" || parts[3] == "" {
+		return "", "", "", "", fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/COMMIT/REPORT-ID", id)
+	}
+	return parts[0], parts[1], parts[2], parts[3], nil
+}
+
+func commitReportEndpoint(workspace, repoSlug, commit, reportID string) string {
+	return fmt.Sprintf("2.0/repositories/%s/%s/commit/%s/reports/%s", workspace, repoSlug, commit, url.PathEscape(reportID))
+}
+
+// expandReportDataValue converts the string value of a data field to the JSON
+// type Bitbucket expects for the field type. DATE values are accepted either
+// as milliseconds since the epoch or as RFC 3339 timestamps, and DURATION
+// values are in milliseconds.
+func expandReportDataValue(dataType, value, linkText string) (json.RawMessage, error) {
+	var v interface{}
+	switch dataType {
+	case "BOOLEAN":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("value %q of a BOOLEAN data field is not a boolean", value)
+		}
+		v = b
+	case "NUMBER", "PERCENTAGE", "DURATION":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q of a %s data field is not a number", value, dataType)
+		}
+		v = f
+	case "DATE":
+		millis, err := reportDateMillis(value)
+		if err != nil {
+			return nil, err
+		}
+		v = millis
+	case "LINK":
+		if linkText == "" {
+			linkText = value
+		}
+		v = CodeInsightsReportLink{Text: linkText, Href: value}
+	default:
+		v = value
+	}
+	return json.Marshal(v)
+}
+
+func reportDateMillis(value string) (int64, error) {
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return millis, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, fmt.Errorf("value %q of a DATE data field is neither milliseconds since the epoch nor an RFC 3339 timestamp", value)
+	}
+	return t.UnixMilli(), nil
+}
+
+// flattenReportDataValue is the inverse of expandReportDataValue.
+func flattenReportDataValue(dataType string, raw json.RawMessage) (value, linkText string) {
+	if dataType == "LINK" {
+		var link CodeInsightsReportLink
+		if err := json.Unmarshal(raw, &link); err == nil {
+			return link.Href, link.Text
+		}
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, ""
+	}
+	var f float64
+	if err := json.Unmarshal(raw, &f); err == nil {
+		return strconv.FormatFloat(f, 'f', -1, 64), ""
+	}
+	return strings.TrimSpace(string(raw)), ""
+}
+
+// suppressEquivalentReportDataValue hides differences between values that
+// Bitbucket stores identically, such as "1.0" and "1" for a NUMBER or an RFC
+// 3339 timestamp and its milliseconds for a DATE.
+func suppressEquivalentReportDataValue(k, old, new string, d *schema.ResourceData) bool {
+	dataType, _ := d.Get(strings.TrimSuffix(k, "value") + "type").(string)
+	switch dataType {
+	case "DATE":
+		o, oerr := reportDateMillis(old)
+		n, nerr := reportDateMillis(new)
+		return oerr == nil && nerr == nil && o == n
+	case "NUMBER", "PERCENTAGE", "DURATION":
+		o, oerr := strconv.ParseFloat(old, 64)
+		n, nerr := strconv.ParseFloat(new, 64)
+		return oerr == nil && nerr == nil && o == n
+	case "BOOLEAN":
+		o, oerr := strconv.ParseBool(old)
+		n, nerr := strconv.ParseBool(new)
+		return oerr == nil && nerr == nil && o == n
+	}
+	return false
+}
+
+// validateCommitReportData checks at plan time that every data value can be
+// converted to its type.
+func validateCommitReportData(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	for i, raw := range d.Get("data").([]interface{}) {
+		field, ok := raw.(map[string]interface{})
+		if !ok || !d.NewValueKnown(fmt.Sprintf("data.%d.value", i)) {
+			continue
+		}
+		linkText, _ := field["link_text"].(string)
+		if _, err := expandReportDataValue(field["type"].(string), field["value"].(string), linkText); err != nil {
+			return fmt.Errorf("data.%d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func expandCommitReport(d *schema.ResourceData) (*CodeInsightsReport, error) {
+	report := &CodeInsightsReport{
+		Title:             d.Get("title").(string),
+		Details:           d.Get("details").(string),
+		ExternalID:        d.Get("report_id").(string),
+		Reporter:          d.Get("reporter").(string),
+		Link:              d.Get("link").(string),
+		LogoURL:           d.Get("logo_url").(string),
+		RemoteLinkEnabled: d.Get("remote_link_enabled").(bool),
+		ReportType:        d.Get("report_type").(string),
+		Result:            d.Get("result").(string),
+		Data:              []CodeInsightsReportData{},
+	}
+
+	for i, raw := range d.Get("data").([]interface{}) {
+		field := raw.(map[string]interface{})
+		value, err := expandReportDataValue(field["type"].(string), field["value"].(string), field["link_text"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("data.%d: %w", i, err)
+		}
+		report.Data = append(report.Data, CodeInsightsReportData{
+			Type:  field["type"].(string),
+			Title: field["title"].(string),
+			Value: value,
+		})
+	}
+
+	return report, nil
+}
+
+func resourceCommitReportPut(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	commit := d.Get("commit").(string)
+	reportID := d.Get("report_id").(string)
+
+	report, err := expandCommitReport(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The reports API creates the report or replaces an existing one.
+	_, err = client.PutContext(ctx, commitReportEndpoint(workspace, repoSlug, commit, reportID), bytes.NewBuffer(payload))
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s/%s", workspace, repoSlug, commit, reportID))
+
+	return resourceCommitReportRead(ctx, d, m)
+}
+
+func resourceCommitReportRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, commit, reportID, err := commitReportId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := client.GetContext(ctx, commitReportEndpoint(workspace, repoSlug, commit, reportID))
+	if IsNotFound(err) {
+		log.Printf("[WARN] Commit Report (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var report CodeInsightsReport
+	if err := json.Unmarshal(body, &report); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data := make([]interface{}, 0, len(report.Data))
+	for _, field := range report.Data {
+		value, linkText := flattenReportDataValue(field.Type, field.Value)
+		data = append(data, map[string]interface{}{
+			"title":     field.Title,
+			"type":      field.Type,
+			"value":     value,
+			"link_text": linkText,
+		})
+	}
+
+	d.Set("workspace", workspace)
+	d.Set("repo_slug", repoSlug)
+	d.Set("commit", commit)
+	d.Set("report_id", reportID)
+	d.Set("title", report.Title)
+	d.Set("details", report.Details)
+	d.Set("report_type", report.ReportType)
+	d.Set("result", report.Result)
+	d.Set("reporter", report.Reporter)
+	d.Set("link", report.Link)
+	d.Set("logo_url", report.LogoURL)
+	d.Set("remote_link_enabled", report.RemoteLinkEnabled)
+	d.Set("data", data)
+	d.Set("uuid", report.UUID)
+	d.Set("created_on", report.CreatedOn)
+	d.Set("updated_on", report.UpdatedOn)
+
+	return nil
+}
+
+func resourceCommitReportDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, commit, reportID, err := commitReportId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_, err = client.DeleteContext(ctx, commitReportEndpoint(workspace, repoSlug, commit, reportID))
+	if err != nil && !IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,301 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// commitReportAnnotationsBatchSize is the maximum number of annotations
+// Bitbucket accepts in one bulk upload.
+const commitReportAnnotationsBatchSize = 100
+
+func resourceCommitReportAnnotations() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceCommitReportAnnotationsPut,
+		ReadWithoutTimeout:   resourceCommitReportAnnotationsRead,
+		UpdateWithoutTimeout: resourceCommitReportAnnotationsPut,
+		DeleteWithoutTimeout: resourceCommitReportAnnotationsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"commit": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Commit hash",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"report_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "External ID or UUID of the report",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"annotation": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MaxItems: 1000,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"external_id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"annotation_type": {
+							Type:     schema.TypeString,
+							Required: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"VULNERABILITY",
+								"CODE_SMELL",
+								"BUG",
+							}, false),
+						},
+						"summary": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"title": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"details": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"path": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"line": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"result": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"PASSED",
+								"FAILED",
+								"SKIPPED",
+								"IGNORED",
+							}, false),
+						},
+						"severity": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"CRITICAL",
+								"HIGH",
+								"MEDIUM",
+								"LOW",
+							}, false),
+						},
+						"link": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// CodeInsightsAnnotation is an annotation of a Code Insights report.
+type CodeInsightsAnnotation struct {
+	UUID           string `json:"uuid,omitempty"`
+	ExternalID     string `json:"external_id"`
+	AnnotationType string `json:"annotation_type"`
+	Summary        string `json:"summary"`
+	Title          string `json:"title,omitempty"`
+	Details        string `json:"details,omitempty"`
+	Path           string `json:"path,omitempty"`
+	Line           int    `json:"line,omitempty"`
+	Result         string `json:"result,omitempty"`
+	Severity       string `json:"severity,omitempty"`
+	Link           string `json:"link,omitempty"`
+}
+
+func expandCommitReportAnnotations(annotations *schema.Set) []CodeInsightsAnnotation {
+	out := make([]CodeInsightsAnnotation, 0, annotations.Len())
+	for _, raw := range annotations.List() {
+		a := raw.(map[string]interface{})
+		out = append(out, CodeInsightsAnnotation{
+			ExternalID:     a["external_id"].(string),
+			AnnotationType: a["annotation_type"].(string),
+			Summary:        a["summary"].(string),
+			Title:          a["title"].(string),
+			Details:        a["details"].(string),
+			Path:           a["path"].(string),
+			Line:           a["line"].(int),
+			Result:         a["result"].(string),
+			Severity:       a["severity"].(string),
+			Link:           a["link"].(string),
+		})
+	}
+	return out
+}
+
+func flattenCommitReportAnnotations(annotations []CodeInsightsAnnotation) []interface{} {
+	out := make([]interface{}, 0, len(annotations))
+	for _, a := range annotations {
+		out = append(out, map[string]interface{}{
+			"external_id":     a.ExternalID,
+			"annotation_type": a.AnnotationType,
+			"summary":         a.Summary,
+			"title":           a.Title,
+			"details":         a.Details,
+			"path":            a.Path,
+			"line":            a.Line,
+			"result":          a.Result,
+			"severity":        a.Severity,
+			"link":            a.Link,
+		})
+	}
+	return out
+}
+
+// batchCommitReportAnnotations splits annotations into bulk upload requests.
+func batchCommitReportAnnotations(annotations []CodeInsightsAnnotation) [][]CodeInsightsAnnotation {
+	var batches [][]CodeInsightsAnnotation
+	for len(annotations) > commitReportAnnotationsBatchSize {
+		batches = append(batches, annotations[:commitReportAnnotationsBatchSize])
+		annotations = annotations[commitReportAnnotationsBatchSize:]
+	}
+	if len(annotations) > 0 {
+		batches = append(batches, annotations)
+	}
+	return batches
+}
+
+func resourceCommitReportAnnotationsPut(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	commit := d.Get("commit").(string)
+	reportID := d.Get("report_id").(string)
+	endpoint := commitReportEndpoint(workspace, repoSlug, commit, reportID) + "/annotations"
+
+	// Annotations dropped from the configuration are deleted; the bulk
+	// upload creates new ones and replaces those with a known external ID.
+	if d.HasChange("annotation") {
+		o, n := d.GetChange("annotation")
+		keep := map[string]bool{}
+		for _, a := range expandCommitReportAnnotations(n.(*schema.Set)) {
+			keep[a.ExternalID] = true
+		}
+		for _, a := range expandCommitReportAnnotations(o.(*schema.Set)) {
+			if keep[a.ExternalID] {
+				continue
+			}
+			_, err := client.DeleteContext(ctx, endpoint+"/"+url.PathEscape(a.ExternalID))
+			if err != nil && !IsNotFound(err) {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	annotations := expandCommitReportAnnotations(d.Get("annotation").(*schema.Set))
+	for i, batch := range batchCommitReportAnnotations(annotations) {
+		payload, err := json.Marshal(batch)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		log.Printf("[DEBUG] Uploading annotations batch %d (%d annotations) to report %s", i+1, len(batch), reportID)
+		_, err = client.PostContext(ctx, endpoint, bytes.NewBuffer(payload))
+		if err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s/%s", workspace, repoSlug, commit, reportID))
+
+	return resourceCommitReportAnnotationsRead(ctx, d, m)
+}
+
+func resourceCommitReportAnnotationsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, commit, reportID, err := commitReportId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	values, err := client.GetPaginatedContext(ctx, commitReportEndpoint(workspace, repoSlug, commit, reportID)+"/annotations?pagelen=100")
+	if IsNotFound(err) {
+		log.Printf("[WARN] Commit Report Annotations (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	annotations := make([]CodeInsightsAnnotation, 0, len(values))
+	for _, raw := range values {
+		var a CodeInsightsAnnotation
+		if err := json.Unmarshal(raw, &a); err != nil {
+			return diag.FromErr(err)
+		}
+		annotations = append(annotations, a)
+	}
+
+	d.Set("workspace", workspace)
+	d.Set("repo_slug", repoSlug)
+	d.Set("commit", commit)
+	d.Set("report_id", reportID)
+	d.Set("annotation", flattenCommitReportAnnotations(annotations))
+
+	return nil
+}
+
+func resourceCommitReportAnnotationsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, commit, reportID, err := commitReportId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	endpoint := commitReportEndpoint(workspace, repoSlug, commit, reportID) + "/annotations"
+
+	for _, a := range expandCommitReportAnnotations(d.Get("annotation").(*schema.Set)) {
+		_, err := client.DeleteContext(ctx, endpoint+"/"+url.PathEscape(a.ExternalID))
+		if IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
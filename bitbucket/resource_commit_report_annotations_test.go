@@ -0,0 +1,168 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccBitbucketCommitReportAnnotations_basic(t *testing.T) {
+	resourceName := "bitbucket_commit_report_annotations.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitReportAnnotationsConfig(workspace, rName, "HIGH"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "annotation.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "annotation.*", map[string]string{
+						"external_id": "tf-acc-1",
+						"severity":    "HIGH",
+					}),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccBitbucketCommitReportAnnotationsConfig(workspace, rName, "LOW"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "annotation.*", map[string]string{
+						"external_id": "tf-acc-1",
+						"severity":    "LOW",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketCommitReportAnnotationsConfig(workspace, rName, severity string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+}
+
+resource "bitbucket_commit_file" "test" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.name
+  branch         = "main"
+  filename       = "README.md"
+  content        = "abc"
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "initial commit"
+}
+
+resource "bitbucket_commit_report" "test" {
+  workspace   = bitbucket_repository.test.owner
+  repo_slug   = bitbucket_repository.test.name
+  commit      = bitbucket_commit_file.test.commit_sha
+  report_id   = "tf-acc-report"
+  title       = "Acceptance report"
+  details     = "Reported by the acceptance tests"
+  report_type = "SECURITY"
+}
+
+resource "bitbucket_commit_report_annotations" "test" {
+  workspace = bitbucket_commit_report.test.workspace
+  repo_slug = bitbucket_commit_report.test.repo_slug
+  commit    = bitbucket_commit_report.test.commit
+  report_id = bitbucket_commit_report.test.report_id
+
+  annotation {
+    external_id     = "tf-acc-1"
+    annotation_type = "VULNERABILITY"
+    summary         = "Hard-coded credential"
+    path            = "README.md"
+    line            = 1
+    severity        = %[3]q
+  }
+
+  annotation {
+    external_id     = "tf-acc-2"
+    annotation_type = "CODE_SMELL"
+    summary         = "Empty readme"
+    result          = "FAILED"
+  }
+}
+`, workspace, rName, severity)
+}
+
+func TestCommitReportAnnotationsUploadInBatches(t *testing.T) {
+	var batches []int
+	var stored []json.RawMessage
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2.0/repositories/acme/platform/commit/a1b2c3/reports/scan-1/annotations" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			var batch []json.RawMessage
+			json.NewDecoder(r.Body).Decode(&batch)
+			batches = append(batches, len(batch))
+			stored = append(stored, batch...)
+			w.Write([]byte(`[]`))
+		case http.MethodGet:
+			// Serve the stored annotations 100 per page.
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			if page == 0 {
+				page = 1
+			}
+			end := page * 100
+			next := ""
+			if end < len(stored) {
+				next = fmt.Sprintf(`"next":"/2.0/repositories/acme/platform/commit/a1b2c3/reports/scan-1/annotations?pagelen=100&page=%d",`, page+1)
+			} else {
+				end = len(stored)
+			}
+			values, _ := json.Marshal(stored[(page-1)*100 : end])
+			fmt.Fprintf(w, `{%s"values":%s}`, next, values)
+		}
+	})
+
+	annotations := make([]interface{}, 0, 250)
+	for i := 0; i < 250; i++ {
+		annotations = append(annotations, map[string]interface{}{
+			"external_id":     fmt.Sprintf("scan-%03d", i),
+			"annotation_type": "VULNERABILITY",
+			"summary":         "Security group allows 0.0.0.0/0",
+			"path":            "main.tf",
+			"line":            i + 1,
+			"severity":        "HIGH",
+		})
+	}
+	d := schema.TestResourceDataRaw(t, resourceCommitReportAnnotations().Schema, map[string]interface{}{
+		"workspace":  "acme",
+		"repo_slug":  "platform",
+		"commit":     "a1b2c3",
+		"report_id":  "scan-1",
+		"annotation": annotations,
+	})
+
+	if diags := resourceCommitReportAnnotationsPut(context.Background(), d, Clients{httpClient: *client}); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !reflect.DeepEqual(batches, []int{100, 100, 50}) {
+		t.Errorf("uploaded batches of %v, want [100 100 50]", batches)
+	}
+	if got := d.Get("annotation").(*schema.Set).Len(); got != 250 {
+		t.Errorf("read back %d annotations, want 250", got)
+	}
+}
@@ -0,0 +1,146 @@
+package bitbucket
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketCommitReport_basic(t *testing.T) {
+	resourceName := "bitbucket_commit_report.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketCommitReportDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitReportConfig(workspace, rName, "PENDING"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "commit", "bitbucket_commit_file.test", "commit_sha"),
+					resource.TestCheckResourceAttr(resourceName, "result", "PENDING"),
+					resource.TestCheckResourceAttr(resourceName, "data.#", "2"),
+					resource.TestCheckResourceAttrSet(resourceName, "uuid"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccBitbucketCommitReportConfig(workspace, rName, "PASSED"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "result", "PASSED"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckBitbucketCommitReportDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(Clients).httpClient
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "bitbucket_commit_report" {
+			continue
+		}
+
+		workspace, repoSlug, commit, reportID, err := commitReportId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Get(commitReportEndpoint(workspace, repoSlug, commit, reportID))
+		if err == nil {
+			return fmt.Errorf("Commit Report %s still exists", rs.Primary.ID)
+		}
+		if !IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccBitbucketCommitReportConfig(workspace, rName, result string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+}
+
+resource "bitbucket_commit_file" "test" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.name
+  branch         = "main"
+  filename       = "README.md"
+  content        = "abc"
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "initial commit"
+}
+
+resource "bitbucket_commit_report" "test" {
+  workspace   = bitbucket_repository.test.owner
+  repo_slug   = bitbucket_repository.test.name
+  commit      = bitbucket_commit_file.test.commit_sha
+  report_id   = "tf-acc-report"
+  title       = "Acceptance report"
+  details     = "Reported by the acceptance tests"
+  report_type = "TEST"
+  result      = %[3]q
+
+  data {
+    title = "Coverage"
+    type  = "PERCENTAGE"
+    value = "87.5"
+  }
+
+  data {
+    title = "Passed"
+    type  = "BOOLEAN"
+    value = "true"
+  }
+}
+`, workspace, rName, result)
+}
+
+func TestReportDataValueRoundTrip(t *testing.T) {
+	cases := []struct {
+		dataType, value, linkText string
+		wantJSON                  string
+		wantValue                 string
+	}{
+		{"BOOLEAN", "true", "", `true`, "true"},
+		{"NUMBER", "42.5", "", `42.5`, "42.5"},
+		{"PERCENTAGE", "85", "", `85`, "85"},
+		{"DURATION", "1500", "", `1500`, "1500"},
+		{"DATE", "2026-01-02T03:04:05Z", "", `1767323045000`, "1767323045000"},
+		{"TEXT", "3 findings", "", `"3 findings"`, "3 findings"},
+		{"LINK", "https://scanner.example.com/r/1", "Full report", `{"text":"Full report","href":"https://scanner.example.com/r/1"}`, "https://scanner.example.com/r/1"},
+	}
+
+	for _, tc := range cases {
+		raw, err := expandReportDataValue(tc.dataType, tc.value, tc.linkText)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.dataType, err)
+		}
+		if string(raw) != tc.wantJSON {
+			t.Errorf("%s: expanded to %s, want %s", tc.dataType, raw, tc.wantJSON)
+		}
+		value, linkText := flattenReportDataValue(tc.dataType, raw)
+		if value != tc.wantValue || linkText != tc.linkText {
+			t.Errorf("%s: flattened to %q/%q, want %q/%q", tc.dataType, value, linkText, tc.wantValue, tc.linkText)
+		}
+	}
+
+	for _, invalid := range [][2]string{{"BOOLEAN", "yes please"}, {"NUMBER", "many"}, {"DATE", "yesterday"}} {
+		if _, err := expandReportDataValue(invalid[0], invalid[1], ""); err == nil {
+			t.Errorf("expected error for %s value %q", invalid[0], invalid[1])
+		}
+	}
+}
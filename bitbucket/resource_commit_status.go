@@ -0,0 +1,227 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceCommitStatus() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceCommitStatusCreate,
+		ReadWithoutTimeout:   resourceCommitStatusRead,
+		UpdateWithoutTimeout: resourceCommitStatusUpdate,
+		DeleteWithoutTimeout: resourceCommitStatusDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"commit": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Commit hash",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"key": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Identifier of the status, unique per commit",
+				ValidateFunc: validation.StringLenBetween(1, 40),
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"SUCCESSFUL",
+					"FAILED",
+					"INPROGRESS",
+					"STOPPED",
+				}, false),
+			},
+			"url": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Link back to the system that produced the status",
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"refname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of the ref that pointed to the commit when the status was reported",
+			},
+			"uuid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_on": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"updated_on": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// CommitStatusInput is the body sent to report a build status.
+type CommitStatusInput struct {
+	Key         string `json:"key"`
+	State       string `json:"state"`
+	URL         string `json:"url"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description"`
+	Refname     string `json:"refname,omitempty"`
+}
+
+func commitStatusId(id string) (workspace, repoSlug, commit, key string, err error) {
+	// The key is last and may itself contain slashes.
+	parts := strings.SplitN(id, "/", 4)
+	if len(parts) != 4 || parts[0] == "" || parts[1] == "" || parts[2] == "" || parts[3] == "" {
+		return "", "", "", "", fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/COMMIT/KEY", id)
+	}
+	return parts[0], parts[1], parts[2], parts[3], nil
+}
+
+func expandCommitStatus(d *schema.ResourceData) CommitStatusInput {
+	return CommitStatusInput{
+		Key:         d.Get("key").(string),
+		State:       d.Get("state").(string),
+		URL:         d.Get("url").(string),
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Refname:     d.Get("refname").(string),
+	}
+}
+
+func resourceCommitStatusCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	commit := d.Get("commit").(string)
+
+	payload, err := json.Marshal(expandCommitStatus(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Posting a status with an existing key replaces it.
+	_, err = client.PostContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/commit/%s/statuses/build", workspace, repoSlug, commit), bytes.NewBuffer(payload))
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s/%s", workspace, repoSlug, commit, d.Get("key").(string)))
+
+	return resourceCommitStatusRead(ctx, d, m)
+}
+
+func resourceCommitStatusRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, commit, key, err := commitStatusId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/commit/%s/statuses/build/%s", workspace, repoSlug, commit, url.PathEscape(key)))
+	if IsNotFound(err) {
+		log.Printf("[WARN] Commit Status (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var status CommitStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("workspace", workspace)
+	d.Set("repo_slug", repoSlug)
+	d.Set("commit", commit)
+	d.Set("key", status.Key)
+	d.Set("state", status.State)
+	d.Set("url", status.URL)
+	d.Set("name", status.Name)
+	d.Set("description", status.Description)
+	d.Set("refname", status.Refname)
+	d.Set("uuid", status.UUID)
+	d.Set("created_on", status.CreatedOn)
+	d.Set("updated_on", status.UpdatedOn)
+
+	return nil
+}
+
+func resourceCommitStatusUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, commit, key, err := commitStatusId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	payload, err := json.Marshal(expandCommitStatus(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_, err = client.PutContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/commit/%s/statuses/build/%s", workspace, repoSlug, commit, url.PathEscape(key)), bytes.NewBuffer(payload))
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
+	return resourceCommitStatusRead(ctx, d, m)
+}
+
+func resourceCommitStatusDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Bitbucket has no API to delete a commit status, so it is only removed
+	// from state.
+	log.Printf("[DEBUG] Commit statuses cannot be deleted, removing %s from state only", d.Id())
+	return nil
+}
@@ -0,0 +1,108 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccBitbucketCommitStatus_basic(t *testing.T) {
+	resourceName := "bitbucket_commit_status.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitStatusConfig(workspace, rName, "INPROGRESS"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "commit", "bitbucket_commit_file.test", "commit_sha"),
+					resource.TestCheckResourceAttr(resourceName, "key", "tf-acc-build"),
+					resource.TestCheckResourceAttr(resourceName, "state", "INPROGRESS"),
+					resource.TestCheckResourceAttrSet(resourceName, "uuid"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccBitbucketCommitStatusConfig(workspace, rName, "SUCCESSFUL"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "state", "SUCCESSFUL"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketCommitStatusConfig(workspace, rName, state string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+}
+
+resource "bitbucket_commit_file" "test" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.name
+  branch         = "main"
+  filename       = "README.md"
+  content        = "abc"
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "initial commit"
+}
+
+resource "bitbucket_commit_status" "test" {
+  workspace   = bitbucket_repository.test.owner
+  repo_slug   = bitbucket_repository.test.name
+  commit      = bitbucket_commit_file.test.commit_sha
+  key         = "tf-acc-build"
+  state       = %[3]q
+  url         = "https://ci.example.com/builds/1"
+  name        = "Acceptance build"
+  description = "Reported by the acceptance tests"
+}
+`, workspace, rName, state)
+}
+
+func TestCommitStatusID(t *testing.T) {
+	workspace, repo, commit, key, err := commitStatusId("acme/platform/a1b2c3/deploy/production")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if workspace != "acme" || repo != "platform" || commit != "a1b2c3" || key != "deploy/production" {
+		t.Errorf("commitStatusId = %q, %q, %q, %q", workspace, repo, commit, key)
+	}
+	if _, _, _, _, err := commitStatusId("acme/platform/a1b2c3"); err == nil {
+		t.Error("expected error for an ID without a key")
+	}
+}
+
+func TestExpandCommitStatus(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCommitStatus().Schema, map[string]interface{}{
+		"workspace": "acme",
+		"repo_slug": "platform",
+		"commit":    "a1b2c3",
+		"key":       "deploy",
+		"state":     "SUCCESSFUL",
+		"url":       "https://ci.example.com/runs/1",
+	})
+
+	payload, err := json.Marshal(expandCommitStatus(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"key":"deploy","state":"SUCCESSFUL","url":"https://ci.example.com/runs/1","description":""}`
+	if string(payload) != want {
+		t.Errorf("payload = %s, want %s", payload, want)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -52,9 +51,9 @@ func resourceDefaultReviewersCreate(ctx context.Context, d *schema.ResourceData,
 	for _, user := range d.Get("reviewers").(*schema.Set).List() {
 		userName := user.(string)
 
-		_, res, err := prApi.RepositoriesWorkspaceRepoSlugDefaultReviewersTargetUsernamePut(c.AuthContext, repo, userName, workspace)
+		_, res, err := prApi.RepositoriesWorkspaceRepoSlugDefaultReviewersTargetUsernamePut(c.requestContext(ctx), repo, userName, workspace)
 		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 
@@ -76,17 +75,17 @@ func resourceDefaultReviewersRead(ctx context.Context, d *schema.ResourceData, m
 	var terraformReviewers []string
 
 	for {
-		reviewers, res, err := prApi.RepositoriesWorkspaceRepoSlugDefaultReviewersGet(c.AuthContext, repo, owner, &options)
-		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
-		}
-
-		if res.StatusCode == http.StatusNotFound {
+		reviewers, res, err := prApi.RepositoriesWorkspaceRepoSlugDefaultReviewersGet(c.requestContext(ctx), repo, owner, &options)
+		if IsNotFound(err) {
 			log.Printf("[WARN] Default Reviewers (%s) not found, removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 
+		if err := handleClientError(res, err); err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+
 		for _, reviewer := range reviewers.Values {
 			terraformReviewers = append(terraformReviewers, reviewer.Uuid)
 		}
@@ -121,17 +120,17 @@ func resourceDefaultReviewersUpdate(ctx context.Context, d *schema.ResourceData,
 
 	for _, user := range add.List() {
 		userName := user.(string)
-		_, res, err := prApi.RepositoriesWorkspaceRepoSlugDefaultReviewersTargetUsernamePut(c.AuthContext, repo, userName, workspace)
+		_, res, err := prApi.RepositoriesWorkspaceRepoSlugDefaultReviewersTargetUsernamePut(c.requestContext(ctx), repo, userName, workspace)
 		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 
 	for _, user := range remove.List() {
 		userName := user.(string)
-		res, err := prApi.RepositoriesWorkspaceRepoSlugDefaultReviewersTargetUsernameDelete(c.AuthContext, repo, userName, workspace)
+		res, err := prApi.RepositoriesWorkspaceRepoSlugDefaultReviewersTargetUsernameDelete(c.requestContext(ctx), repo, userName, workspace)
 		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 
@@ -146,9 +145,9 @@ func resourceDefaultReviewersDelete(ctx context.Context, d *schema.ResourceData,
 	workspace := d.Get("owner").(string)
 	for _, user := range d.Get("reviewers").(*schema.Set).List() {
 		userName := user.(string)
-		res, err := prApi.RepositoriesWorkspaceRepoSlugDefaultReviewersTargetUsernameDelete(c.AuthContext, repo, userName, workspace)
+		res, err := prApi.RepositoriesWorkspaceRepoSlugDefaultReviewersTargetUsernameDelete(c.requestContext(ctx), repo, userName, workspace)
 		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 	return nil
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -69,10 +68,10 @@ func resourceDeployKeysCreate(ctx context.Context, d *schema.ResourceData, m int
 
 	repo := d.Get("repository").(string)
 	workspace := d.Get("workspace").(string)
-	deployKeyReq, err := client.Post(fmt.Sprintf("2.0/repositories/%s/%s/deploy-keys", workspace, repo), bytes.NewBuffer(bytedata))
+	deployKeyReq, err := client.PostContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/deploy-keys", workspace, repo), bytes.NewBuffer(bytedata))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(deployKeyReq.Body)
@@ -105,16 +104,16 @@ func resourceDeployKeysRead(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.FromErr(err)
 	}
 
-	deployKey, res, err := deployApi.RepositoriesWorkspaceRepoSlugDeployKeysKeyIdGet(c.AuthContext, keyId, repo, workspace)
+	deployKey, res, err := deployApi.RepositoriesWorkspaceRepoSlugDeployKeysKeyIdGet(c.requestContext(ctx), keyId, repo, workspace)
 
-	if res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Deploy Key (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	log.Printf("[DEBUG] Deploy Key Response: %#v", deployKey)
@@ -145,7 +144,7 @@ func resourceDeployKeysUpdate(ctx context.Context, d *schema.ResourceData, m int
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Put(fmt.Sprintf("2.0/repositories/%s/%s/deploy-keys/%s",
+	_, err = client.PutContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/deploy-keys/%s",
 		workspace, repo, keyId), bytes.NewBuffer(bytedata))
 
 	if err != nil {
@@ -164,9 +163,9 @@ func resourceDeployKeysDelete(ctx context.Context, d *schema.ResourceData, m int
 		return diag.FromErr(err)
 	}
 
-	res, err := deployApi.RepositoriesWorkspaceRepoSlugDeployKeysKeyIdDelete(c.AuthContext, keyId, repo, workspace)
+	res, err := deployApi.RepositoriesWorkspaceRepoSlugDeployKeysKeyIdDelete(c.requestContext(ctx), keyId, repo, workspace)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return diag.FromErr(err)
@@ -7,7 +7,7 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
+	"reflect"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -17,10 +17,15 @@ import (
 
 // Deployment structure for handling key info
 type Deployment struct {
-	Name         string        `json:"name"`
-	Stage        *Stage        `json:"environment_type"`
-	UUID         string        `json:"uuid,omitempty"`
-	Restrictions *Restrictions `json:"restrictions,omitempty"`
+	Name                  string          `json:"name"`
+	Stage                 *Stage          `json:"environment_type"`
+	UUID                  string          `json:"uuid,omitempty"`
+	Rank                  *int            `json:"rank,omitempty"`
+	Hidden                bool            `json:"hidden,omitempty"`
+	Lock                  *DeploymentLock `json:"lock,omitempty"`
+	DeploymentGateEnabled bool            `json:"deployment_gate_enabled,omitempty"`
+	DeploymentGateCheck   json.RawMessage `json:"deployment_gate_check,omitempty"`
+	Restrictions          *Restrictions   `json:"restrictions,omitempty"`
 }
 
 type Stage struct {
@@ -28,16 +33,39 @@ type Stage struct {
 }
 
 type Restrictions struct {
-	AdminOnly bool `json:"admin_only"`
+	AdminOnly          bool                          `json:"admin_only"`
+	BranchRestrictions []DeploymentBranchRestriction `json:"branch_restrictions"`
+}
+
+type DeploymentBranchRestriction struct {
+	Pattern string `json:"pattern"`
+}
+
+// DeploymentLock is the lock of an environment, named OPEN or LOCKED.
+type DeploymentLock struct {
+	Type string `json:"type,omitempty"`
+	Name string `json:"name"`
+}
+
+func (l *DeploymentLock) locked() bool {
+	return l != nil && l.Name == "LOCKED"
 }
 
 type Changes struct {
 	Change *Change `json:"change"`
 }
 
+// Change holds the settings of an environment to change; unset fields are
+// left as they are.
 type Change struct {
-	Name         string       `json:"name,omitempty"`
-	Restrictions Restrictions `json:"restrictions,omitempty"`
+	Name                  string          `json:"name,omitempty"`
+	Stage                 *Stage          `json:"environment_type,omitempty"`
+	Rank                  *int            `json:"rank,omitempty"`
+	Hidden                *bool           `json:"hidden,omitempty"`
+	Lock                  *DeploymentLock `json:"lock,omitempty"`
+	DeploymentGateEnabled *bool           `json:"deployment_gate_enabled,omitempty"`
+	DeploymentGateCheck   json.RawMessage `json:"deployment_gate_check,omitempty"`
+	Restrictions          *Restrictions   `json:"restrictions,omitempty"`
 }
 
 func resourceDeployment() *schema.Resource {
@@ -49,6 +77,7 @@ func resourceDeployment() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: deploymentRankDiff,
 
 		Schema: map[string]*schema.Schema{
 			"uuid": {
@@ -62,7 +91,6 @@ func resourceDeployment() *schema.Resource {
 			"stage": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 				ValidateFunc: validation.StringInSlice([]string{
 					"Test",
 					"Staging",
@@ -90,10 +118,81 @@ func resourceDeployment() *schema.Resource {
 					},
 				},
 			},
+			"branch_restrictions": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				Description: "Branches that can be deployed to the environment",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"pattern": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "Branch name or glob pattern, such as release/*",
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+					},
+				},
+			},
+			"rank": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Position of the environment among the environments of its stage",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"hidden": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the environment is hidden from the deployments dashboard",
+			},
+			"locked": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the environment is locked against deployments",
+			},
+			"deployment_gate": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Whether deployments wait for the gate check to pass",
+						},
+						"check": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Computed:         true,
+							Description:      "Settings of the gate check, as a JSON object",
+							ValidateFunc:     validateDeploymentGateCheck,
+							DiffSuppressFunc: suppressEquivalentDeploymentGateCheck,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
+// deploymentRankDiff marks the rank as unknown when the stage changes and no
+// rank is configured, as the environment then gets a rank in its new stage.
+func deploymentRankDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("stage") {
+		return nil
+	}
+	if config := d.GetRawConfig(); config.IsNull() || !config.GetAttr("rank").IsNull() {
+		return nil
+	}
+	return d.SetNewComputed("rank")
+}
+
 func newDeploymentFromResource(d *schema.ResourceData) *Deployment {
 	dk := &Deployment{
 		Name: d.Get("name").(string),
@@ -102,14 +201,79 @@ func newDeploymentFromResource(d *schema.ResourceData) *Deployment {
 		},
 	}
 
-	if v, ok := d.GetOk("restrictions"); ok {
-		rest := expandRestrictions(v.([]interface{}))
+	_, hasRestrictions := d.GetOk("restrictions")
+	_, hasBranchRestrictions := d.GetOk("branch_restrictions")
+	if hasRestrictions || hasBranchRestrictions {
+		rest := expandRestrictions(d)
 		dk.Restrictions = &rest
 	}
 
+	// nolint:staticcheck
+	if v, ok := d.GetOkExists("rank"); ok {
+		rank := v.(int)
+		dk.Rank = &rank
+	}
+
 	return dk
 }
 
+// newDeploymentChange returns the settings to change. On create only the
+// settings that cannot be given when the environment is created are
+// included, on update every changed setting.
+func newDeploymentChange(d *schema.ResourceData, create bool) (*Change, bool) {
+	change := &Change{}
+	changed := false
+
+	if !create {
+		if d.HasChange("name") {
+			change.Name = d.Get("name").(string)
+			changed = true
+		}
+		if d.HasChange("stage") {
+			change.Stage = &Stage{Name: d.Get("stage").(string)}
+			changed = true
+		}
+		// A rank that is not configured is left to Bitbucket, in particular
+		// when the environment moves to another stage.
+		config := d.GetRawConfig()
+		if d.HasChange("rank") && (config.IsNull() || !config.GetAttr("rank").IsNull()) {
+			rank := d.Get("rank").(int)
+			change.Rank = &rank
+			changed = true
+		}
+		if d.HasChanges("restrictions", "branch_restrictions") {
+			rest := expandRestrictions(d)
+			change.Restrictions = &rest
+			changed = true
+		}
+	}
+
+	if hidden := d.Get("hidden").(bool); d.HasChange("hidden") && (hidden || !create) {
+		change.Hidden = &hidden
+		changed = true
+	}
+
+	// nolint:staticcheck
+	if v, ok := d.GetOkExists("locked"); ok && d.HasChange("locked") && (v.(bool) || !create) {
+		change.Lock = &DeploymentLock{Name: "OPEN"}
+		if v.(bool) {
+			change.Lock.Name = "LOCKED"
+		}
+		changed = true
+	}
+
+	if d.HasChange("deployment_gate") {
+		enabled, check := expandDeploymentGate(d.Get("deployment_gate").([]interface{}))
+		if enabled || !create {
+			change.DeploymentGateEnabled = &enabled
+			change.DeploymentGateCheck = check
+			changed = true
+		}
+	}
+
+	return change, changed
+}
+
 func resourceDeploymentCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
 	client := m.(Clients).httpClient
@@ -119,12 +283,12 @@ func resourceDeploymentCreate(ctx context.Context, d *schema.ResourceData, m int
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	req, err := client.Post(fmt.Sprintf("2.0/repositories/%s/environments/",
+	req, err := client.PostContext(ctx, fmt.Sprintf("2.0/repositories/%s/environments/",
 		d.Get("repository").(string),
 	), bytes.NewBuffer(bytedata))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	var deployment Deployment
@@ -146,6 +310,12 @@ func resourceDeploymentCreate(ctx context.Context, d *schema.ResourceData, m int
 	d.Set("uuid", deployment.UUID)
 	d.SetId(fmt.Sprintf("%s:%s", d.Get("repository"), deployment.UUID))
 
+	if change, ok := newDeploymentChange(d, true); ok {
+		if err := postDeploymentChange(ctx, &client, d.Get("repository").(string), deployment.UUID, change); err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+	}
+
 	return resourceDeploymentRead(ctx, d, m)
 }
 
@@ -157,12 +327,12 @@ func resourceDeploymentRead(ctx context.Context, d *schema.ResourceData, m inter
 	}
 
 	client := m.(Clients).httpClient
-	res, err := client.Get(fmt.Sprintf("2.0/repositories/%s/environments/%s",
+	res, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/environments/%s",
 		repoId,
 		deployId,
 	))
 
-	if res != nil && res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Deployment (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -189,9 +359,18 @@ func resourceDeploymentRead(ctx context.Context, d *schema.ResourceData, m inter
 
 	d.Set("uuid", deploy.UUID)
 	d.Set("name", deploy.Name)
-	d.Set("stage", deploy.Stage.Name)
+	if deploy.Stage != nil {
+		d.Set("stage", deploy.Stage.Name)
+	}
 	d.Set("repository", repoId)
 	d.Set("restrictions", flattenRestrictions(deploy.Restrictions))
+	d.Set("branch_restrictions", flattenDeploymentBranchRestrictions(deploy.Restrictions))
+	if deploy.Rank != nil {
+		d.Set("rank", *deploy.Rank)
+	}
+	d.Set("hidden", deploy.Hidden)
+	d.Set("locked", deploy.Lock.locked())
+	d.Set("deployment_gate", flattenDeploymentGate(deploy.DeploymentGateEnabled, deploy.DeploymentGateCheck))
 
 	return nil
 }
@@ -199,44 +378,34 @@ func resourceDeploymentRead(ctx context.Context, d *schema.ResourceData, m inter
 func resourceDeploymentUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(Clients).httpClient
 
-	rvcr := &Changes{
-		Change: &Change{},
-	}
-
-	if d.HasChange("name") {
-		rvcr.Change.Name = d.Get("name").(string)
+	change, ok := newDeploymentChange(d, false)
+	if !ok {
+		return resourceDeploymentRead(ctx, d, m)
 	}
 
-	if d.HasChange("restrictions") {
-		rvcr.Change.Restrictions = expandRestrictions(d.Get("restrictions").([]interface{}))
+	if err := postDeploymentChange(ctx, &client, d.Get("repository").(string), d.Get("uuid").(string), change); err != nil {
+		return apiErrorDiagnostics(err, d)
 	}
 
-	log.Printf("[DEBUG] deployment update req: %#v", rvcr)
-
-	bytedata, err := json.Marshal(rvcr)
+	return resourceDeploymentRead(ctx, d, m)
+}
 
+// postDeploymentChange applies change to the environment. Settings that are
+// not part of change are kept.
+func postDeploymentChange(ctx context.Context, client *Client, repository, uuid string, change *Change) error {
+	bytedata, err := json.Marshal(&Changes{Change: change})
 	if err != nil {
-		return diag.FromErr(err)
+		return err
 	}
 
 	log.Printf("[DEBUG] deployment update req encoded: %v", string(bytedata))
 
-	req, err := client.Post(fmt.Sprintf("2.0/repositories/%s/environments/%s/changes/",
-		d.Get("repository").(string),
-		d.Get("uuid").(string),
+	_, err = client.PostContext(ctx, fmt.Sprintf("2.0/repositories/%s/environments/%s/changes/",
+		repository,
+		uuid,
 	), bytes.NewBuffer(bytedata))
 
-	log.Printf("[DEBUG] deployment update res: %#v", req)
-
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	if req.StatusCode != 200 {
-		return nil
-	}
-
-	return resourceDeploymentRead(ctx, d, m)
+	return err
 }
 
 func resourceDeploymentDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -248,19 +417,30 @@ func resourceDeploymentDelete(ctx context.Context, d *schema.ResourceData, m int
 		return diag.FromErr(err)
 	}
 
-	res, err := deployApi.DeleteEnvironmentForRepository(c.AuthContext, workspaceId, repoId, d.Get("uuid").(string))
+	res, err := deployApi.DeleteEnvironmentForRepository(c.requestContext(ctx), workspaceId, repoId, d.Get("uuid").(string))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return nil
 }
 
-func expandRestrictions(conf []interface{}) Restrictions {
-	tfMap, _ := conf[0].(map[string]interface{})
-
+func expandRestrictions(d *schema.ResourceData) Restrictions {
 	target := Restrictions{
-		AdminOnly: tfMap["admin_only"].(bool),
+		BranchRestrictions: []DeploymentBranchRestriction{},
+	}
+
+	if conf := d.Get("restrictions").([]interface{}); len(conf) > 0 {
+		if tfMap, ok := conf[0].(map[string]interface{}); ok {
+			target.AdminOnly = tfMap["admin_only"].(bool)
+		}
+	}
+
+	for _, raw := range d.Get("branch_restrictions").([]interface{}) {
+		tfMap, _ := raw.(map[string]interface{})
+		target.BranchRestrictions = append(target.BranchRestrictions, DeploymentBranchRestriction{
+			Pattern: tfMap["pattern"].(string),
+		})
 	}
 
 	return target
@@ -278,6 +458,70 @@ func flattenRestrictions(rp *Restrictions) []interface{} {
 	return []interface{}{m}
 }
 
+func flattenDeploymentBranchRestrictions(rp *Restrictions) []interface{} {
+	if rp == nil {
+		return []interface{}{}
+	}
+
+	restrictions := make([]interface{}, 0, len(rp.BranchRestrictions))
+	for _, r := range rp.BranchRestrictions {
+		restrictions = append(restrictions, map[string]interface{}{
+			"pattern": r.Pattern,
+		})
+	}
+
+	return restrictions
+}
+
+// expandDeploymentGate returns the gate settings. The check is sent as
+// configured, so its values keep their JSON types.
+func expandDeploymentGate(conf []interface{}) (bool, json.RawMessage) {
+	if len(conf) == 0 || conf[0] == nil {
+		return false, nil
+	}
+
+	tfMap := conf[0].(map[string]interface{})
+	var check json.RawMessage
+	if v := tfMap["check"].(string); v != "" {
+		check = json.RawMessage(v)
+	}
+	return tfMap["enabled"].(bool), check
+}
+
+func flattenDeploymentGate(enabled bool, check json.RawMessage) []interface{} {
+	value := strings.TrimSpace(string(check))
+	if value == "null" {
+		value = ""
+	}
+	if !enabled && value == "" {
+		return []interface{}{}
+	}
+
+	return []interface{}{map[string]interface{}{
+		"enabled": enabled,
+		"check":   value,
+	}}
+}
+
+func validateDeploymentGateCheck(val interface{}, key string) (warns []string, errs []error) {
+	var check map[string]interface{}
+	if err := json.Unmarshal([]byte(val.(string)), &check); err != nil || check == nil {
+		errs = append(errs, fmt.Errorf("%q must be a JSON object", key))
+	}
+	return
+}
+
+// suppressEquivalentDeploymentGateCheck hides differences in the formatting
+// of the check, such as whitespace or the order of keys, which Bitbucket does
+// not keep.
+func suppressEquivalentDeploymentGateCheck(k, old, new string, d *schema.ResourceData) bool {
+	var o, n interface{}
+	if json.Unmarshal([]byte(old), &o) != nil || json.Unmarshal([]byte(new), &n) != nil {
+		return false
+	}
+	return reflect.DeepEqual(o, n)
+}
+
 func deploymentId(id string) (string, string, error) {
 	parts := strings.Split(id, ":")
 
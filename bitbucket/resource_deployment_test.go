@@ -1,6 +1,8 @@
 package bitbucket
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -111,6 +113,48 @@ func TestAccBitbucketDeployment_admin(t *testing.T) {
 	})
 }
 
+func TestAccBitbucketDeployment_settings(t *testing.T) {
+	var deploy Deployment
+
+	resourceName := "bitbucket_deployment.test"
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	owner := os.Getenv("BITBUCKET_TEAM")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketDeploymentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketDeploymentSettings(owner, rName, true, "release/*"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketDeploymentExists(resourceName, &deploy),
+					resource.TestCheckResourceAttr(resourceName, "hidden", "true"),
+					resource.TestCheckResourceAttr(resourceName, "locked", "true"),
+					resource.TestCheckResourceAttr(resourceName, "branch_restrictions.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "branch_restrictions.0.pattern", "release/*"),
+					resource.TestCheckResourceAttrSet(resourceName, "rank"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccBitbucketDeploymentSettings(owner, rName, false, "main"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketDeploymentExists(resourceName, &deploy),
+					resource.TestCheckResourceAttr(resourceName, "hidden", "false"),
+					resource.TestCheckResourceAttr(resourceName, "locked", "false"),
+					resource.TestCheckResourceAttr(resourceName, "branch_restrictions.0.pattern", "main"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckBitbucketDeploymentDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(Clients).httpClient
 	rs, ok := s.RootModule().Resources["bitbucket_deployment.test"]
@@ -173,3 +217,215 @@ resource "bitbucket_deployment" "test" {
 }
 `, workspace, repoName, deployName, admin)
 }
+
+func testAccBitbucketDeploymentSettings(workspace, rName string, hiddenAndLocked bool, pattern string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+}
+
+resource "bitbucket_deployment" "test" {
+  name       = %[2]q
+  stage      = "Staging"
+  repository = bitbucket_repository.test.id
+  hidden     = %[3]t
+  locked     = %[3]t
+
+  branch_restrictions {
+    pattern = %[4]q
+  }
+}
+`, workspace, rName, hiddenAndLocked, pattern)
+}
+
+func TestDeploymentLifecycle(t *testing.T) {
+	env := map[string]interface{}{}
+	var created map[string]interface{}
+	var changes []map[string]interface{}
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/2.0/repositories/acme/platform/environments/" && r.Method == http.MethodPost:
+			json.NewDecoder(r.Body).Decode(&created)
+			for k, v := range created {
+				env[k] = v
+			}
+			env["uuid"] = "{e1}"
+			env["rank"] = 2
+			env["lock"] = map[string]interface{}{"type": "deployment_environment_lock_open", "name": "OPEN"}
+			json.NewEncoder(w).Encode(env)
+		case r.URL.Path == "/2.0/repositories/acme/platform/environments/{e1}" && r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(env)
+		case r.URL.Path == "/2.0/repositories/acme/platform/environments/{e1}/changes/" && r.Method == http.MethodPost:
+			var body struct {
+				Change map[string]interface{} `json:"change"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			changes = append(changes, body.Change)
+			for k, v := range body.Change {
+				env[k] = v
+			}
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+	meta := Clients{httpClient: *client}
+	r := resourceDeployment()
+	ctx := context.Background()
+
+	apply := func(state *terraform.InstanceState, raw map[string]interface{}) *terraform.InstanceState {
+		t.Helper()
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state != nil && diff.RequiresNew() {
+			t.Fatalf("unexpected replacement: %v", diff)
+		}
+		state, diags := r.Apply(ctx, state, diff, meta)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		return state
+	}
+	raw := map[string]interface{}{
+		"repository":          "acme/platform",
+		"name":                "production-eu",
+		"stage":               "Production",
+		"hidden":              true,
+		"branch_restrictions": []interface{}{map[string]interface{}{"pattern": "release/*"}},
+		"deployment_gate":     []interface{}{map[string]interface{}{"enabled": true}},
+	}
+
+	// Settings that cannot be given on creation are applied right after.
+	state := apply(nil, raw)
+	if _, ok := created["hidden"]; ok || created["restrictions"].(map[string]interface{})["branch_restrictions"] == nil {
+		t.Errorf("unexpected create request: %v", created)
+	}
+	if len(changes) != 1 || changes[0]["hidden"] != true || changes[0]["deployment_gate_enabled"] != true || len(changes[0]) != 2 {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+	if state.ID != "acme/platform:{e1}" || state.Attributes["rank"] != "2" || state.Attributes["locked"] != "false" ||
+		state.Attributes["branch_restrictions.0.pattern"] != "release/*" || state.Attributes["deployment_gate.0.enabled"] != "true" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+
+	// The stage changes in place, and only the changed settings are sent.
+	changes = nil
+	raw["stage"] = "Staging"
+	raw["locked"] = true
+	state = apply(state, raw)
+	if len(changes) != 1 || changes[0]["environment_type"].(map[string]interface{})["name"] != "Staging" ||
+		changes[0]["lock"].(map[string]interface{})["name"] != "LOCKED" || len(changes[0]) != 2 {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+	if state.Attributes["stage"] != "Staging" || state.Attributes["locked"] != "true" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+
+	// Gate check values keep their JSON types, and formatting that Bitbucket
+	// does not keep shows no difference.
+	changes = nil
+	raw["deployment_gate"] = []interface{}{map[string]interface{}{
+		"enabled": true,
+		"check":   "{\n  \"provider\": \"jira\",\n  \"min_approvals\": 2,\n  \"strict\": true\n}",
+	}}
+	state = apply(state, raw)
+	check, _ := changes[0]["deployment_gate_check"].(map[string]interface{})
+	if len(changes) != 1 || check["min_approvals"] != float64(2) || check["strict"] != true || check["provider"] != "jira" {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+	state, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta); err != nil || !diff.Empty() {
+		t.Errorf("unexpected diff after refresh: %v, %v", diff, err)
+	}
+
+	// Settings that are no longer declared are left as they are; the gate is
+	// disabled explicitly.
+	changes = nil
+	raw["deployment_gate"] = []interface{}{map[string]interface{}{"enabled": false}}
+	delete(raw, "branch_restrictions")
+	raw["rank"] = 0
+	state = apply(state, raw)
+	if len(changes) != 1 || changes[0]["deployment_gate_enabled"] != false || changes[0]["rank"] != float64(0) {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+	if state.Attributes["deployment_gate.0.enabled"] != "false" || state.Attributes["branch_restrictions.0.pattern"] != "release/*" || state.Attributes["rank"] != "0" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+}
+
+func TestDeploymentUpgradeKeepsSettings(t *testing.T) {
+	env := map[string]interface{}{
+		"uuid":                    "{e1}",
+		"name":                    "production-eu",
+		"environment_type":        map[string]interface{}{"name": "Production"},
+		"rank":                    0,
+		"hidden":                  true,
+		"lock":                    map[string]interface{}{"name": "OPEN"},
+		"deployment_gate_enabled": true,
+		"deployment_gate_check":   map[string]interface{}{"provider": "jira"},
+		"restrictions": map[string]interface{}{
+			"admin_only":          false,
+			"branch_restrictions": []interface{}{map[string]interface{}{"pattern": "release/*"}},
+		},
+	}
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2.0/repositories/acme/platform/environments/{e1}" || r.Method != http.MethodGet {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewEncoder(w).Encode(env)
+	})
+	meta := Clients{httpClient: *client}
+	r := resourceDeployment()
+	ctx := context.Background()
+
+	// State written by an earlier version of the provider, for an environment
+	// whose settings were made in the Bitbucket UI.
+	state := &terraform.InstanceState{
+		ID: "acme/platform:{e1}",
+		Attributes: map[string]string{
+			"id":                        "acme/platform:{e1}",
+			"uuid":                      "{e1}",
+			"name":                      "production-eu",
+			"stage":                     "Production",
+			"repository":                "acme/platform",
+			"restrictions.#":            "1",
+			"restrictions.0.admin_only": "false",
+		},
+	}
+	state, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":       "production-eu",
+		"stage":      "Production",
+		"repository": "acme/platform",
+	}), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Errorf("unexpected diff after upgrade: %v", diff)
+	}
+}
+
+func TestValidateDeploymentGateCheck(t *testing.T) {
+	for value, valid := range map[string]bool{
+		`{"min_approvals": 2}`: true,
+		`{}`:                   true,
+		`[1, 2]`:               false,
+		`null`:                 false,
+		`min_approvals = 2`:    false,
+	} {
+		if _, errs := validateDeploymentGateCheck(value, "check"); (len(errs) == 0) != valid {
+			t.Errorf("%s: unexpected errors %v", value, errs)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 	"time"
 
@@ -83,15 +82,18 @@ func resourceDeploymentVariableCreate(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(err)
 	}
 
-	rvRes, res, err := pipeApi.CreateDeploymentVariable(c.AuthContext, *rvcr, workspace, repoSlug, deployment)
+	rvRes, res, err := pipeApi.CreateDeploymentVariable(c.requestContext(ctx), *rvcr, workspace, repoSlug, deployment)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.Set("uuid", rvRes.Uuid)
 	d.SetId(rvRes.Uuid)
 
-	time.Sleep(5000 * time.Millisecond) // sleep for a while, to allow BitBucket cache to catch up
+	// sleep for a while, to allow BitBucket cache to catch up
+	if err := sleepContext(ctx, 5000*time.Millisecond); err != nil {
+		return diag.FromErr(err)
+	}
 	return resourceDeploymentVariableRead(ctx, d, m)
 }
 
@@ -105,16 +107,16 @@ func resourceDeploymentVariableRead(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	rvRes, res, err := pipeApi.GetDeploymentVariables(c.AuthContext, workspace, repoSlug, deployment, nil)
+	rvRes, res, err := pipeApi.GetDeploymentVariables(c.requestContext(ctx), workspace, repoSlug, deployment, nil)
 
-	if res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Deployment Variable (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	if rvRes.Size < 1 {
@@ -162,9 +164,9 @@ func resourceDeploymentVariableUpdate(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(err)
 	}
 
-	_, res, err := pipeApi.UpdateDeploymentVariable(c.AuthContext, *rvcr, workspace, repoSlug, deployment, d.Get("uuid").(string))
+	_, res, err := pipeApi.UpdateDeploymentVariable(c.requestContext(ctx), *rvcr, workspace, repoSlug, deployment, d.Get("uuid").(string))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourceDeploymentVariableRead(ctx, d, m)
@@ -180,9 +182,9 @@ func resourceDeploymentVariableDelete(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(err)
 	}
 
-	res, err := pipeApi.DeleteDeploymentVariable(c.AuthContext, workspace, repoSlug, deployment, d.Get("uuid").(string))
+	res, err := pipeApi.DeleteDeploymentVariable(c.requestContext(ctx), workspace, repoSlug, deployment, d.Get("uuid").(string))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return nil
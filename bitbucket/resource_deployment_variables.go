@@ -0,0 +1,410 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceDeploymentVariables() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceDeploymentVariablesCreate,
+		ReadWithoutTimeout:   resourceDeploymentVariablesRead,
+		UpdateWithoutTimeout: resourceDeploymentVariablesUpdate,
+		DeleteWithoutTimeout: resourceDeploymentVariablesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				if _, _, _, err := deploymentVariablesId(d.Id()); err != nil {
+					return nil, err
+				}
+				d.Set("deployment", d.Id())
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"deployment": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the deployment environment, as exported by bitbucket_deployment",
+			},
+			"variable": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"value": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
+						},
+						"value_wo": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							WriteOnly:   true,
+							Description: "Value that is written but never stored in state. Change `rotation_id` to write a new value",
+						},
+						"secured": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"rotation_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Arbitrary value that, when changed, writes the variable again",
+						},
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"write_only": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the value was written from `value_wo`",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// DeploymentVariable is a variable of a deployment environment.
+type DeploymentVariable struct {
+	Type    string `json:"type,omitempty"`
+	UUID    string `json:"uuid,omitempty"`
+	Key     string `json:"key"`
+	Value   string `json:"value,omitempty"`
+	Secured bool   `json:"secured"`
+}
+
+// deploymentVariablesId splits the ID of a deployment environment, in the
+// form WORKSPACE/REPO-SLUG:ENVIRONMENT-UUID.
+func deploymentVariablesId(id string) (string, string, string, error) {
+	repository, environment, ok := strings.Cut(id, ":")
+	workspace, repoSlug, err := deployVarId(repository)
+	if !ok || environment == "" || err != nil || workspace == "" || repoSlug == "" {
+		return "", "", "", fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG:ENVIRONMENT-UUID", id)
+	}
+	return workspace, repoSlug, environment, nil
+}
+
+func deploymentVariablesEndpoint(id string) (string, error) {
+	workspace, repoSlug, environment, err := deploymentVariablesId(id)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("2.0/repositories/%s/%s/deployments_config/environments/%s/variables", workspace, repoSlug, environment), nil
+}
+
+func getDeploymentVariables(ctx context.Context, client *Client, endpoint string) (map[string]DeploymentVariable, error) {
+	values, err := client.GetPaginatedContext(ctx, endpoint+"?pagelen=100")
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make(map[string]DeploymentVariable, len(values))
+	for _, raw := range values {
+		var v DeploymentVariable
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+		variables[v.Key] = v
+	}
+	return variables, nil
+}
+
+// desiredDeploymentVariable is a configured variable with the value to write.
+type desiredDeploymentVariable struct {
+	DeploymentVariable
+	rotationID string
+	writeOnly  bool
+}
+
+// expandDeploymentVariables returns the configured variables by key. Values
+// of `value_wo` only exist in the configuration, never in the plan.
+func expandDeploymentVariables(d *schema.ResourceData) (map[string]desiredDeploymentVariable, error) {
+	desired := map[string]desiredDeploymentVariable{}
+	for i, raw := range d.Get("variable").([]interface{}) {
+		v := raw.(map[string]interface{})
+		key := v["key"].(string)
+		if _, ok := desired[key]; ok {
+			return nil, fmt.Errorf("variable %q is declared more than once", key)
+		}
+
+		value := v["value"].(string)
+		wo := deploymentVariableValueWO(d, i)
+		if wo != nil {
+			if value != "" {
+				return nil, fmt.Errorf("variable %q sets both value and value_wo", key)
+			}
+			value = *wo
+		}
+
+		desired[key] = desiredDeploymentVariable{
+			DeploymentVariable: DeploymentVariable{
+				Key:     key,
+				Value:   value,
+				Secured: v["secured"].(bool),
+			},
+			rotationID: v["rotation_id"].(string),
+			writeOnly:  wo != nil,
+		}
+	}
+	return desired, nil
+}
+
+// deploymentVariableValueWO returns the `value_wo` of the i-th variable, or nil
+// when it is not set.
+func deploymentVariableValueWO(d *schema.ResourceData, i int) *string {
+	if d.GetRawConfig().IsNull() {
+		return nil
+	}
+	wo, diags := d.GetRawConfigAt(cty.GetAttrPath("variable").IndexInt(i).GetAttr("value_wo"))
+	if diags.HasError() || wo.IsNull() || !wo.IsKnown() {
+		return nil
+	}
+	value := wo.AsString()
+	return &value
+}
+
+// deploymentVariablesByKey indexes the variables of a state or plan.
+func deploymentVariablesByKey(list interface{}) map[string]map[string]interface{} {
+	byKey := map[string]map[string]interface{}{}
+	for _, raw := range list.([]interface{}) {
+		v := raw.(map[string]interface{})
+		byKey[v["key"].(string)] = v
+	}
+	return byKey
+}
+
+func resourceDeploymentVariablesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId(d.Get("deployment").(string))
+
+	if diags := syncDeploymentVariables(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	return resourceDeploymentVariablesRead(ctx, d, m)
+}
+
+func resourceDeploymentVariablesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := syncDeploymentVariables(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	return resourceDeploymentVariablesRead(ctx, d, m)
+}
+
+// syncDeploymentVariables makes the variables of the environment match the
+// configuration. Variables that are not declared are deleted; declared ones
+// are written when they are missing, when their value, `secured` or
+// `rotation_id` changed, or when they were dropped from state as drifted.
+func syncDeploymentVariables(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	endpoint, err := deploymentVariablesEndpoint(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desired, err := expandDeploymentVariables(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	remote, err := getDeploymentVariables(ctx, &client, endpoint)
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
+	old, _ := d.GetChange("variable")
+	written := deploymentVariablesByKey(old)
+
+	for key, v := range remote {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		log.Printf("[DEBUG] Deleting deployment variable %s of %s", key, d.Id())
+		if _, err := client.DeleteContext(ctx, fmt.Sprintf("%s/%s", endpoint, v.UUID)); err != nil && !IsNotFound(err) {
+			return diag.Errorf("error deleting variable %s: %s", key, err)
+		}
+	}
+
+	keys := make([]string, 0, len(desired))
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	uuids := map[string]string{}
+	for _, key := range keys {
+		v := desired[key]
+
+		// Write-only values are not in state to compare with; only a new
+		// rotation_id writes them again.
+		current, exists := remote[key]
+		prev, tracked := written[key]
+		if exists && tracked &&
+			prev["uuid"] == current.UUID &&
+			prev["write_only"] == v.writeOnly && (v.writeOnly || prev["value"] == v.Value) &&
+			prev["secured"] == v.Secured && current.Secured == v.Secured &&
+			prev["rotation_id"] == v.rotationID {
+			uuids[key] = current.UUID
+			continue
+		}
+
+		payload, err := json.Marshal(v.DeploymentVariable)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		var res *http.Response
+		if exists {
+			log.Printf("[DEBUG] Updating deployment variable %s of %s", key, d.Id())
+			res, err = client.PutContext(ctx, fmt.Sprintf("%s/%s", endpoint, current.UUID), bytes.NewBuffer(payload))
+		} else {
+			log.Printf("[DEBUG] Creating deployment variable %s of %s", key, d.Id())
+			res, err = client.PostContext(ctx, endpoint, bytes.NewBuffer(payload))
+		}
+		if err != nil {
+			return diag.Errorf("error writing variable %s: %s", key, err)
+		}
+
+		var result DeploymentVariable
+		err = json.NewDecoder(res.Body).Decode(&result)
+		res.Body.Close()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		uuids[key] = result.UUID
+	}
+
+	// The configuration is not available when refreshing, so Read relies on
+	// the state to know which values were written from value_wo. Computed
+	// attributes of the plan follow the position in the list, not the key,
+	// so the UUIDs are set again as well.
+	variables := d.Get("variable").([]interface{})
+	for _, raw := range variables {
+		v := raw.(map[string]interface{})
+		v["write_only"] = desired[v["key"].(string)].writeOnly
+		v["uuid"] = uuids[v["key"].(string)]
+	}
+	d.Set("variable", variables)
+
+	return nil
+}
+
+func resourceDeploymentVariablesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	endpoint, err := deploymentVariablesEndpoint(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	remote, err := getDeploymentVariables(ctx, &client, endpoint)
+	if IsNotFound(err) {
+		log.Printf("[WARN] Deployment Variables (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Variables keep their order in state. A variable that changed outside
+	// Terraform in a way that cannot be shown as a difference of its
+	// attributes is dropped, so the next apply writes it again.
+	variables := make([]interface{}, 0, len(remote))
+	seen := map[string]bool{}
+	for _, raw := range d.Get("variable").([]interface{}) {
+		v := raw.(map[string]interface{})
+		key := v["key"].(string)
+		current, ok := remote[key]
+		if !ok {
+			log.Printf("[WARN] Deployment variable %s of %s not found", key, d.Id())
+			continue
+		}
+		seen[key] = true
+
+		// Neither secured nor write-only values can be compared, so only a
+		// variable that was replaced is noticed.
+		writeOnly := v["write_only"].(bool)
+		if (current.Secured && v["secured"].(bool) || writeOnly) && v["uuid"] != "" && v["uuid"] != current.UUID {
+			log.Printf("[WARN] Deployment variable %s of %s was recreated", key, d.Id())
+			continue
+		}
+
+		if !current.Secured && !writeOnly {
+			v["value"] = current.Value
+		}
+
+		v["uuid"] = current.UUID
+		v["secured"] = current.Secured
+		variables = append(variables, v)
+	}
+
+	// Undeclared variables are added so the plan removes them.
+	var strays []string
+	for key := range remote {
+		if !seen[key] {
+			strays = append(strays, key)
+		}
+	}
+	sort.Strings(strays)
+	for _, key := range strays {
+		current := remote[key]
+		variables = append(variables, map[string]interface{}{
+			"key":     key,
+			"value":   current.Value,
+			"secured": current.Secured,
+			"uuid":    current.UUID,
+		})
+	}
+
+	d.Set("deployment", d.Id())
+	d.Set("variable", variables)
+
+	return nil
+}
+
+func resourceDeploymentVariablesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	endpoint, err := deploymentVariablesEndpoint(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, raw := range d.Get("variable").([]interface{}) {
+		v := raw.(map[string]interface{})
+		if v["uuid"] == "" {
+			continue
+		}
+		_, err := client.DeleteContext(ctx, fmt.Sprintf("%s/%s", endpoint, v["uuid"]))
+		if err != nil && !IsNotFound(err) {
+			return diag.Errorf("error deleting variable %s: %s", v["key"], err)
+		}
+	}
+
+	return nil
+}
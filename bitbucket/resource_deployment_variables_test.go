@@ -0,0 +1,251 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketDeploymentVariables_basic(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	owner := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_deployment_variables.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketDeploymentVariablesConfig(owner, rName, "eu-west-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "deployment", "bitbucket_deployment.test", "id"),
+					resource.TestCheckResourceAttr(resourceName, "variable.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "variable.0.key", "REGION"),
+					resource.TestCheckResourceAttr(resourceName, "variable.0.value", "eu-west-1"),
+					resource.TestCheckResourceAttrSet(resourceName, "variable.0.uuid"),
+					resource.TestCheckResourceAttr(resourceName, "variable.1.key", "TOKEN"),
+					resource.TestCheckResourceAttr(resourceName, "variable.1.secured", "true"),
+					resource.TestCheckResourceAttrSet(resourceName, "variable.1.uuid"),
+				),
+			},
+			{
+				Config: testAccBitbucketDeploymentVariablesConfig(owner, rName, "us-east-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "variable.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "variable.0.value", "us-east-1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketDeploymentVariablesConfig(owner, rName, region string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+}
+
+resource "bitbucket_deployment" "test" {
+  name       = %[2]q
+  stage      = "Test"
+  repository = bitbucket_repository.test.id
+}
+
+resource "bitbucket_deployment_variables" "test" {
+  deployment = bitbucket_deployment.test.id
+
+  variable {
+    key   = "REGION"
+    value = %[3]q
+  }
+
+  variable {
+    key         = "TOKEN"
+    value_wo    = "s3cr3t"
+    secured     = true
+    rotation_id = "1"
+  }
+}
+`, owner, rName, region)
+}
+
+// fakeDeploymentVariables is an in-memory deployment environment variables
+// endpoint.
+type fakeDeploymentVariables struct {
+	t       *testing.T
+	vars    map[string]DeploymentVariable
+	next    int
+	written []string
+	deleted []string
+}
+
+func (f *fakeDeploymentVariables) handler(w http.ResponseWriter, r *http.Request) {
+	const endpoint = "/2.0/repositories/acme/platform/deployments_config/environments/{e1}/variables"
+	uuid := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, endpoint), "/")
+	byUUID := func() (string, bool) {
+		for key, v := range f.vars {
+			if v.UUID == uuid {
+				return key, true
+			}
+		}
+		return "", false
+	}
+
+	switch {
+	case !strings.HasPrefix(r.URL.Path, endpoint):
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	case r.Method == http.MethodGet:
+		var values []DeploymentVariable
+		for _, v := range f.vars {
+			if v.Secured {
+				v.Value = ""
+			}
+			values = append(values, v)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"values": values})
+	case r.Method == http.MethodPost || r.Method == http.MethodPut:
+		var v DeploymentVariable
+		json.NewDecoder(r.Body).Decode(&v)
+		if r.Method == http.MethodPost {
+			f.next++
+			v.UUID = fmt.Sprintf("{v%d}", f.next)
+		} else if _, ok := byUUID(); !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else {
+			v.UUID = uuid
+		}
+		f.vars[v.Key] = v
+		f.written = append(f.written, r.Method+" "+v.Key+"="+v.Value)
+		json.NewEncoder(w).Encode(v)
+	case r.Method == http.MethodDelete:
+		key, ok := byUUID()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.vars, key)
+		f.deleted = append(f.deleted, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestDeploymentVariablesLifecycle(t *testing.T) {
+	server := &fakeDeploymentVariables{t: t, next: 100, vars: map[string]DeploymentVariable{
+		"STRAY": {UUID: "{stray}", Key: "STRAY", Value: "old"},
+	}}
+	client, _ := newMockServerClient(t, server.handler)
+	meta := Clients{httpClient: *client}
+	r := resourceDeploymentVariables()
+	ctx := context.Background()
+
+	// Write-only values never reach the plan; they are only part of the raw
+	// configuration.
+	secret := "s3cr3t"
+	apply := func(state *terraform.InstanceState, variables []map[string]interface{}) *terraform.InstanceState {
+		t.Helper()
+		raw := map[string]interface{}{"deployment": "acme/platform:{e1}"}
+		list := []interface{}{}
+		elems := []cty.Value{}
+		for _, v := range variables {
+			list = append(list, v)
+			attrs := map[string]cty.Value{"key": cty.StringVal(v["key"].(string)), "value_wo": cty.NullVal(cty.String)}
+			if v["value"] == nil {
+				attrs["value_wo"] = cty.StringVal(secret)
+			}
+			elems = append(elems, cty.ObjectVal(attrs))
+		}
+		raw["variable"] = list
+
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff == nil || diff.Empty() {
+			return state
+		}
+		diff.RawConfig, err = r.CoreConfigSchema().CoerceValue(cty.ObjectVal(map[string]cty.Value{
+			"deployment": cty.StringVal("acme/platform:{e1}"),
+			"variable":   cty.ListVal(elems),
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		state, diags := r.Apply(ctx, state, diff, meta)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		return state
+	}
+	refresh := func(state *terraform.InstanceState) *terraform.InstanceState {
+		t.Helper()
+		state, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		return state
+	}
+	config := []map[string]interface{}{
+		{"key": "REGION", "value": "eu-west-1"},
+		{"key": "TOKEN", "secured": true, "rotation_id": "1"},
+	}
+
+	state := apply(nil, config)
+	if !reflect.DeepEqual(server.deleted, []string{"STRAY"}) || !reflect.DeepEqual(server.written, []string{"POST REGION=eu-west-1", "POST TOKEN=s3cr3t"}) {
+		t.Fatalf("deleted %v, written %v", server.deleted, server.written)
+	}
+	if state.Attributes["variable.1.write_only"] != "true" || state.Attributes["variable.0.write_only"] != "false" || state.Attributes["variable.1.value"] != "" || state.Attributes["variable.1.uuid"] != "{v102}" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+	for k, v := range state.Attributes {
+		if v == secret || v == contentSHA256(secret) {
+			t.Errorf("write-only value leaked into state as %s", k)
+		}
+	}
+
+	// Without changes nothing is written.
+	server.written = nil
+	state = apply(refresh(state), config)
+	if server.written != nil {
+		t.Errorf("unexpected writes: %v", server.written)
+	}
+
+	// Outside changes to a plain value, a replaced secured variable and a
+	// stray variable are all reverted.
+	server.vars["REGION"] = DeploymentVariable{UUID: "{v101}", Key: "REGION", Value: "us-east-1"}
+	server.vars["TOKEN"] = DeploymentVariable{UUID: "{v200}", Key: "TOKEN", Value: "leaked", Secured: true}
+	server.vars["DEBUG"] = DeploymentVariable{UUID: "{v201}", Key: "DEBUG", Value: "true"}
+	state = refresh(state)
+	if state.Attributes["variable.0.value"] != "us-east-1" || state.Attributes["variable.#"] != "2" || state.Attributes["variable.1.key"] != "DEBUG" {
+		t.Fatalf("drift not detected: %v", state.Attributes)
+	}
+	state = apply(state, config)
+	if server.vars["REGION"].Value != "eu-west-1" || server.vars["TOKEN"].Value != secret || len(server.vars) != 2 {
+		t.Errorf("drift not reverted: %v", server.vars)
+	}
+	if state.Attributes["variable.1.uuid"] != "{v200}" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+
+	// A new rotation_id writes the write-only value again.
+	server.written = nil
+	secret = "r0tated"
+	config[1]["rotation_id"] = "2"
+	state = apply(refresh(state), config)
+	if !reflect.DeepEqual(server.written, []string{"PUT TOKEN=r0tated"}) || state.Attributes["variable.1.value"] != "" {
+		t.Errorf("written %v, state %v", server.written, state.Attributes)
+	}
+}
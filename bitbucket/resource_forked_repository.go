@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -210,20 +209,27 @@ func resourceForkedRepositoryCreate(ctx context.Context, d *schema.ResourceData,
 	repoBody := &bitbucket.RepositoriesApiRepositoriesWorkspaceRepoSlugForksPostOpts{
 		Body: optional.NewInterface(requestRepo),
 	}
-	_, res, err := repoApi.RepositoriesWorkspaceRepoSlugForksPost(c.AuthContext, parentRepoSlug, parentWorkspace, repoBody)
+	_, res, err := repoApi.RepositoriesWorkspaceRepoSlugForksPost(c.requestContext(ctx), parentRepoSlug, parentWorkspace, repoBody)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s", d.Get("owner").(string), repoSlug))
 
+	// The fork is created asynchronously; wait until it is ready so that
+	// resources depending on it do not fail with a 404.
+	client := m.(Clients).httpClient
+	if err := waitForForkReady(ctx, &client, workspace, repoSlug, parentWorkspace, parentRepoSlug); err != nil {
+		return diag.FromErr(err)
+	}
+
 	pipelinesEnabled := d.Get("pipelines_enabled").(bool)
 	pipelinesConfig := &bitbucket.PipelinesConfig{Enabled: pipelinesEnabled}
 
 	//nolint:all
 	retryErr := resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		_, pipelineResponse, err := pipeApi.UpdateRepositoryPipelineConfig(c.AuthContext, *pipelinesConfig, workspace, repoSlug)
-		if pipelineResponse.StatusCode == 403 || pipelineResponse.StatusCode == 404 {
+		_, pipelineResponse, err := pipeApi.UpdateRepositoryPipelineConfig(c.requestContext(ctx), *pipelinesConfig, workspace, repoSlug)
+		if IsForbidden(err) || IsNotFound(err) {
 			return resource.RetryableError(
 				fmt.Errorf("permissions error setting Pipelines config, retrying"),
 			)
@@ -241,6 +247,17 @@ func resourceForkedRepositoryCreate(ctx context.Context, d *schema.ResourceData,
 	return resourceRepositoryRead(ctx, d, m)
 }
 
+// waitForForkReady waits until the fork can be read and its main branch is
+// visible. The fork of an empty repository never gets a main branch, so it is
+// ready as soon as it can be read.
+func waitForForkReady(ctx context.Context, client *Client, workspace, repoSlug, parentWorkspace, parentRepoSlug string) error {
+	parentEmpty, err := repositoryEmpty(ctx, client, parentWorkspace, parentRepoSlug)
+	if err != nil {
+		return fmt.Errorf("error reading repository %s/%s: %w", parentWorkspace, parentRepoSlug, err)
+	}
+	return waitForRepositoryReady(ctx, client, workspace, repoSlug, !parentEmpty)
+}
+
 func resourceForkedRepositoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	id := d.Id()
 	if id != "" {
@@ -265,16 +282,16 @@ func resourceForkedRepositoryRead(ctx context.Context, d *schema.ResourceData, m
 	repoApi := c.ApiClient.RepositoriesApi
 	pipeApi := c.ApiClient.PipelinesApi
 
-	repoRes, res, err := repoApi.RepositoriesWorkspaceRepoSlugGet(c.AuthContext, repoSlug, workspace)
+	repoRes, res, err := repoApi.RepositoriesWorkspaceRepoSlugGet(c.requestContext(ctx), repoSlug, workspace)
 
-	if res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Repository (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.Set("scm", repoRes.Scm)
@@ -311,16 +328,13 @@ func resourceForkedRepositoryRead(ctx context.Context, d *schema.ResourceData, m
 
 	d.Set("link", flattenLinks(repoRes.Links))
 
-	pipelinesConfigReq, res, err := pipeApi.GetRepositoryPipelineConfig(c.AuthContext, workspace, repoSlug)
-	if err := handleClientError(res, err); err != nil && res.StatusCode != http.StatusNotFound {
+	pipelinesConfigReq, res, err := pipeApi.GetRepositoryPipelineConfig(c.requestContext(ctx), workspace, repoSlug)
+	if IsNotFound(err) {
+		d.Set("pipelines_enabled", false)
+	} else if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
-	}
-
-	switch res.StatusCode {
-	case http.StatusOK:
+	} else {
 		d.Set("pipelines_enabled", pipelinesConfigReq.Enabled)
-	case http.StatusNotFound:
-		d.Set("pipelines_enabled", false)
 	}
 
 	return nil
@@ -1,12 +1,18 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/DrFaust92/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccBitbucketForkedRepository_basic(t *testing.T) {
@@ -127,3 +133,101 @@ resource "bitbucket_forked_repository" "test" {
 }
 `, testUser, rName)
 }
+
+func TestWaitForForkReady(t *testing.T) {
+	defer func(interval time.Duration) { repositoryReadyPollInterval = interval }(repositoryReadyPollInterval)
+	repositoryReadyPollInterval = time.Millisecond
+
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2.0/repositories/acme/empty/refs/branches", "/2.0/repositories/acme/empty-fork/refs/branches":
+			w.Write([]byte(`{"values":[]}`))
+		case "/2.0/repositories/acme/seeded/refs/branches":
+			w.Write([]byte(`{"values":[{"name":"main","target":{"hash":"abc"}}]}`))
+		case "/2.0/repositories/acme/empty-fork":
+			w.Write([]byte(`{"slug":"empty-fork","mainbranch":{"name":"main"}}`))
+		case "/2.0/repositories/acme/empty-fork/refs/branches/main":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type":"error","error":{"message":"Branch not found"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	// The fork of an empty repository has no main branch to wait for.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := waitForForkReady(ctx, client, "acme", "empty-fork", "acme", "empty"); err != nil {
+		t.Errorf("fork of an empty repository not ready: %v", err)
+	}
+
+	// The fork of a repository with commits waits for its main branch.
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := waitForForkReady(ctx, client, "acme", "empty-fork", "acme", "seeded"); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestForkedRepositoryLifecycle(t *testing.T) {
+	fork := `{"slug":"my-fork","name":"my-fork","scm":"git","is_private":true,"fork_policy":"no_public_forks"}`
+	var requests []string
+	client, server := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /2.0/repositories/acme/upstream/forks", "GET /2.0/repositories/acme/my-fork", "PUT /2.0/repositories/acme/my-fork":
+			w.Write([]byte(fork))
+		case "GET /2.0/repositories/acme/upstream/refs/branches", "GET /2.0/repositories/acme/my-fork/refs/branches":
+			w.Write([]byte(`{"values":[]}`))
+		case "PUT /2.0/repositories/acme/my-fork/pipelines_config", "GET /2.0/repositories/acme/my-fork/pipelines_config":
+			w.Write([]byte(`{"enabled":false}`))
+		case "GET /2.0/repositories/acme/my-fork/override-settings":
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+	conf := bitbucket.NewConfiguration()
+	conf.BasePath = apiBasePath(server.URL)
+	conf.HTTPClient = server.Client()
+	meta := Clients{
+		genClient:  ProviderConfig{ApiClient: bitbucket.NewAPIClient(conf)},
+		httpClient: *client,
+	}
+	r := resourceForkedRepository()
+	ctx := context.Background()
+
+	apply := func(state *terraform.InstanceState, raw map[string]interface{}) *terraform.InstanceState {
+		t.Helper()
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state, diags := r.Apply(ctx, state, diff, meta)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		return state
+	}
+	raw := map[string]interface{}{
+		"owner":  "acme",
+		"name":   "my-fork",
+		"parent": map[string]interface{}{"owner": "acme", "slug": "upstream"},
+	}
+
+	state := apply(nil, raw)
+	if state.ID != "acme/my-fork" || state.Attributes["scm"] != "git" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+
+	requests = nil
+	raw["description"] = "A fork"
+	state = apply(state, raw)
+	if len(requests) == 0 || requests[0] != "PUT /2.0/repositories/acme/my-fork" {
+		t.Errorf("unexpected requests: %v", requests)
+	}
+	if state.ID != "acme/my-fork" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+}
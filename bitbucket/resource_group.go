@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -72,9 +71,9 @@ func resourceGroupsCreate(ctx context.Context, d *schema.ResourceData, m interfa
 
 	workspace := d.Get("workspace").(string)
 	body := []byte(fmt.Sprintf("name=%s", group.Name))
-	groupReq, err := client.PostNonJson(fmt.Sprintf("1.0/groups/%s", workspace), bytes.NewBuffer(body))
+	groupReq, err := client.PostNonJsonContext(ctx, fmt.Sprintf("1.0/groups/%s", workspace), bytes.NewBuffer(body))
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(groupReq.Body)
@@ -104,14 +103,18 @@ func resourceGroupsRead(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.FromErr(err)
 	}
 
-	groupsReq, _ := client.Get(fmt.Sprintf("1.0/groups/%s/%s", workspace, slug))
+	groupsReq, err := client.GetContext(ctx, fmt.Sprintf("1.0/groups/%s/%s", workspace, slug))
 
-	if groupsReq.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Group (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	if groupsReq.Body == nil {
 		return diag.Errorf("error reading Group (%s): empty response", d.Id())
 	}
@@ -153,11 +156,11 @@ func resourceGroupsUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Put(fmt.Sprintf("1.0/groups/%s/%s/",
+	_, err = client.PutContext(ctx, fmt.Sprintf("1.0/groups/%s/%s/",
 		d.Get("workspace").(string), d.Get("slug").(string)), bytes.NewBuffer(bytedata))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourceGroupsRead(ctx, d, m)
@@ -171,7 +174,7 @@ func resourceGroupsDelete(ctx context.Context, d *schema.ResourceData, m interfa
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Delete(fmt.Sprintf("1.0/groups/%s/%s", workspace, slug))
+	_, err = client.DeleteContext(ctx, fmt.Sprintf("1.0/groups/%s/%s", workspace, slug))
 
 	if err != nil {
 		return diag.FromErr(err)
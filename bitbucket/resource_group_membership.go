@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -57,10 +56,10 @@ func resourceGroupMembershipsPut(ctx context.Context, d *schema.ResourceData, m
 	groupSlug := d.Get("group_slug").(string)
 	uuid := d.Get("uuid").(string)
 
-	_, err := client.PutOnly(fmt.Sprintf("1.0/groups/%s/%s/members/%s",
+	_, err := client.PutOnlyContext(ctx, fmt.Sprintf("1.0/groups/%s/%s/members/%s",
 		workspace, groupSlug, uuid))
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s/%s", workspace, groupSlug, uuid))
@@ -76,14 +75,18 @@ func resourceGroupMembershipsRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	groupsReq, _ := client.Get(fmt.Sprintf("1.0/groups/%s/%s/members", workspace, slug))
+	groupsReq, err := client.GetContext(ctx, fmt.Sprintf("1.0/groups/%s/%s/members", workspace, slug))
 
-	if groupsReq.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Group Membership (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	if groupsReq.Body == nil {
 		return diag.Errorf("error reading Group Membership (%s): empty response", d.Id())
 	}
@@ -137,7 +140,7 @@ func resourceGroupMembershipsDelete(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Delete(fmt.Sprintf("1.0/groups/%s/%s/members/%s",
+	_, err = client.DeleteContext(ctx, fmt.Sprintf("1.0/groups/%s/%s/members/%s",
 		workspace, slug, uuid))
 
 	if err != nil {
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"net/url"
 	"strings"
 
@@ -164,13 +163,13 @@ func resourceHookCreate(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.FromErr(err)
 	}
 
-	hookReq, err := client.Post(fmt.Sprintf("2.0/repositories/%s/%s/hooks",
+	hookReq, err := client.PostContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/hooks",
 		d.Get("owner").(string),
 		d.Get("repository").(string),
 	), bytes.NewBuffer(payload))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(hookReq.Body)
@@ -190,13 +189,13 @@ func resourceHookCreate(ctx context.Context, d *schema.ResourceData, m interface
 func resourceHookRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(Clients).httpClient
 
-	hookReq, err := client.Get(fmt.Sprintf("2.0/repositories/%s/%s/hooks/%s",
+	hookReq, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/hooks/%s",
 		d.Get("owner").(string),
 		d.Get("repository").(string),
 		url.PathEscape(d.Id()),
 	))
 
-	if hookReq.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Repository Hook (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -245,14 +244,14 @@ func resourceHookUpdate(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Put(fmt.Sprintf("2.0/repositories/%s/%s/hooks/%s",
+	_, err = client.PutContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/hooks/%s",
 		d.Get("owner").(string),
 		d.Get("repository").(string),
 		url.PathEscape(d.Id()),
 	), bytes.NewBuffer(payload))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourceHookRead(ctx, d, m)
@@ -260,7 +259,7 @@ func resourceHookUpdate(ctx context.Context, d *schema.ResourceData, m interface
 
 func resourceHookDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(Clients).httpClient
-	_, err := client.Delete(fmt.Sprintf("2.0/repositories/%s/%s/hooks/%s",
+	_, err := client.DeleteContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/hooks/%s",
 		d.Get("owner").(string),
 		d.Get("repository").(string),
 		url.PathEscape(d.Id()),
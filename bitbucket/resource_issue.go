@@ -0,0 +1,404 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/DrFaust92/bitbucket-go-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceIssue() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceIssueCreate,
+		ReadWithoutTimeout:   resourceIssueRead,
+		UpdateWithoutTimeout: resourceIssueUpdate,
+		DeleteWithoutTimeout: resourceIssueDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				workspace, repoSlug, _, err := issueId(d.Id())
+				if err != nil {
+					return nil, err
+				}
+				d.Set("workspace", workspace)
+				d.Set("repo_slug", repoSlug)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"title": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the issue, in Markdown",
+			},
+			"kind": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "bug",
+				ValidateFunc: validation.StringInSlice([]string{
+					"bug",
+					"enhancement",
+					"proposal",
+					"task",
+				}, false),
+			},
+			"priority": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "major",
+				ValidateFunc: validation.StringInSlice([]string{
+					"trivial",
+					"minor",
+					"major",
+					"critical",
+					"blocker",
+				}, false),
+			},
+			"assignee": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "UUID of the account the issue is assigned to",
+			},
+			"component": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of a component defined in the repository's issue tracker",
+			},
+			"milestone": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of a milestone defined in the repository's issue tracker",
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of a version defined in the repository's issue tracker",
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"new",
+					"open",
+					"submitted",
+					"resolved",
+					"on hold",
+					"invalid",
+					"duplicate",
+					"wontfix",
+					"closed",
+				}, false),
+			},
+			"issue_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"reporter": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_on": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"updated_on": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// IssueInput is the body sent to create or update an issue. Unset
+// references are sent as null so that updates clear them.
+type IssueInput struct {
+	Title     string            `json:"title"`
+	Content   IssueContent      `json:"content"`
+	Kind      string            `json:"kind"`
+	Priority  string            `json:"priority"`
+	Assignee  *IssueAccount     `json:"assignee"`
+	Component *IssueNamedObject `json:"component"`
+	Milestone *IssueNamedObject `json:"milestone"`
+	Version   *IssueNamedObject `json:"version"`
+}
+
+// IssueContent is the rendered text of an issue.
+type IssueContent struct {
+	Raw string `json:"raw"`
+}
+
+// IssueAccount references a Bitbucket account by UUID.
+type IssueAccount struct {
+	UUID string `json:"uuid"`
+}
+
+// IssueNamedObject references a component, milestone or version by name.
+type IssueNamedObject struct {
+	Name string `json:"name"`
+}
+
+// ManagedIssue is an issue as returned by the issue tracker API.
+type ManagedIssue struct {
+	ID        int                       `json:"id"`
+	Title     string                    `json:"title"`
+	Content   IssueContent              `json:"content"`
+	State     string                    `json:"state"`
+	Kind      string                    `json:"kind"`
+	Priority  string                    `json:"priority"`
+	Assignee  *IssueAccount             `json:"assignee"`
+	Reporter  *IssueAccount             `json:"reporter"`
+	Component *IssueNamedObject         `json:"component"`
+	Milestone *IssueNamedObject         `json:"milestone"`
+	Version   *IssueNamedObject         `json:"version"`
+	CreatedOn string                    `json:"created_on"`
+	UpdatedOn string                    `json:"updated_on"`
+	Links     map[string]bitbucket.Link `json:"links"`
+}
+
+// IssueStateChange is the body sent to the changes endpoint to transition the
+// state of an issue.
+type IssueStateChange struct {
+	Changes struct {
+		State struct {
+			New string `json:"new"`
+		} `json:"state"`
+	} `json:"changes"`
+}
+
+func issueId(id string) (string, string, string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/ISSUE-ID", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func issueNamedObject(d *schema.ResourceData, key string) *IssueNamedObject {
+	if v, ok := d.GetOk(key); ok {
+		return &IssueNamedObject{Name: v.(string)}
+	}
+	return nil
+}
+
+func expandIssueInput(d *schema.ResourceData) IssueInput {
+	input := IssueInput{
+		Title:     d.Get("title").(string),
+		Content:   IssueContent{Raw: d.Get("content").(string)},
+		Kind:      d.Get("kind").(string),
+		Priority:  d.Get("priority").(string),
+		Component: issueNamedObject(d, "component"),
+		Milestone: issueNamedObject(d, "milestone"),
+		Version:   issueNamedObject(d, "version"),
+	}
+	if v, ok := d.GetOk("assignee"); ok {
+		input.Assignee = &IssueAccount{UUID: v.(string)}
+	}
+	return input
+}
+
+func resourceIssueCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+
+	payload, err := json.Marshal(expandIssueInput(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := client.PostContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/issues", workspace, repoSlug), bytes.NewBuffer(payload))
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
+	issue, err := decodeManagedIssue(res)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%d", workspace, repoSlug, issue.ID))
+	log.Printf("[DEBUG] Created issue %d in repository %s/%s", issue.ID, workspace, repoSlug)
+
+	// New issues always start in the "new" state; any other state is reached
+	// through a change.
+	if v, ok := d.GetOk("state"); ok && v.(string) != issue.State {
+		if err := changeIssueState(ctx, &client, d.Id(), v.(string)); err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+	}
+
+	return resourceIssueRead(ctx, d, m)
+}
+
+func resourceIssueRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, id, err := issueId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/issues/%s", workspace, repoSlug, id))
+	if IsNotFound(err) {
+		log.Printf("[WARN] Issue (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	issue, err := decodeManagedIssue(res)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("workspace", workspace)
+	d.Set("repo_slug", repoSlug)
+	d.Set("issue_id", issue.ID)
+	d.Set("title", issue.Title)
+	d.Set("content", issue.Content.Raw)
+	d.Set("kind", issue.Kind)
+	d.Set("priority", issue.Priority)
+	d.Set("state", issue.State)
+	d.Set("assignee", "")
+	if issue.Assignee != nil {
+		d.Set("assignee", issue.Assignee.UUID)
+	}
+	d.Set("reporter", "")
+	if issue.Reporter != nil {
+		d.Set("reporter", issue.Reporter.UUID)
+	}
+	for key, ref := range map[string]*IssueNamedObject{
+		"component": issue.Component,
+		"milestone": issue.Milestone,
+		"version":   issue.Version,
+	} {
+		d.Set(key, "")
+		if ref != nil {
+			d.Set(key, ref.Name)
+		}
+	}
+	d.Set("created_on", issue.CreatedOn)
+	d.Set("updated_on", issue.UpdatedOn)
+	d.Set("url", issue.Links["html"].Href)
+
+	return nil
+}
+
+func resourceIssueUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, id, err := issueId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChanges("title", "content", "kind", "priority", "assignee", "component", "milestone", "version") {
+		payload, err := json.Marshal(expandIssueInput(d))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		_, err = client.PutContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/issues/%s", workspace, repoSlug, id), bytes.NewBuffer(payload))
+		if err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+	}
+
+	if d.HasChange("state") {
+		if v, ok := d.GetOk("state"); ok {
+			if err := changeIssueState(ctx, &client, d.Id(), v.(string)); err != nil {
+				return apiErrorDiagnostics(err, d)
+			}
+		}
+	}
+
+	return resourceIssueRead(ctx, d, m)
+}
+
+func resourceIssueDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, id, err := issueId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_, err = client.DeleteContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/issues/%s", workspace, repoSlug, id))
+	if err != nil && !IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// changeIssueState moves an issue to a new state. Bitbucket records the
+// transition in the issue's change log, as it would for a change made in the
+// UI.
+func changeIssueState(ctx context.Context, client *Client, id, state string) error {
+	workspace, repoSlug, issueID, err := issueId(id)
+	if err != nil {
+		return err
+	}
+
+	var change IssueStateChange
+	change.Changes.State.New = state
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Changing state of issue %s to %q", id, state)
+	_, err = client.PostContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/issues/%s/changes", workspace, repoSlug, issueID), bytes.NewBuffer(payload))
+	return err
+}
+
+func decodeManagedIssue(res *http.Response) (*ManagedIssue, error) {
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue ManagedIssue
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
@@ -0,0 +1,154 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketIssue_basic(t *testing.T) {
+	resourceName := "bitbucket_issue.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketIssueDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketIssueConfig(workspace, rName, "new"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "title", "Acceptance issue"),
+					resource.TestCheckResourceAttr(resourceName, "kind", "task"),
+					resource.TestCheckResourceAttr(resourceName, "state", "new"),
+					resource.TestCheckResourceAttrSet(resourceName, "issue_id"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccBitbucketIssueConfig(workspace, rName, "resolved"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "state", "resolved"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckBitbucketIssueDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(Clients).httpClient
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "bitbucket_issue" {
+			continue
+		}
+
+		workspace, repoSlug, id, err := issueId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Get(fmt.Sprintf("2.0/repositories/%s/%s/issues/%s", workspace, repoSlug, id))
+		if err == nil {
+			return fmt.Errorf("Issue %s still exists", rs.Primary.ID)
+		}
+		if !IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccBitbucketIssueConfig(workspace, rName, state string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  has_issues = true
+}
+
+resource "bitbucket_issue" "test" {
+  workspace = bitbucket_repository.test.owner
+  repo_slug = bitbucket_repository.test.name
+  title     = "Acceptance issue"
+  content   = "Opened by the acceptance tests"
+  kind      = "task"
+  priority  = "minor"
+  state     = %[3]q
+}
+`, workspace, rName, state)
+}
+
+func TestIssueID(t *testing.T) {
+	workspace, repo, id, err := issueId("acme/platform/7")
+	if err != nil || workspace != "acme" || repo != "platform" || id != "7" {
+		t.Errorf("issueId = %q, %q, %q, %v", workspace, repo, id, err)
+	}
+	if _, _, _, err := issueId("acme/platform"); err == nil {
+		t.Error("expected an error for an ID without the issue number")
+	}
+}
+
+func TestIssueCreateTransitionsState(t *testing.T) {
+	var created IssueInput
+	var change IssueStateChange
+	state := "new"
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/2.0/repositories/acme/platform/issues":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":7,"state":"new"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/2.0/repositories/acme/platform/issues/7/changes":
+			json.NewDecoder(r.Body).Decode(&change)
+			state = change.Changes.State.New
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/2.0/repositories/acme/platform/issues/7":
+			fmt.Fprintf(w, `{"id":7,"title":"Seed issue","content":{"raw":"Body"},"kind":"task","priority":"minor","state":%q,
+				"component":{"name":"api"},"milestone":null,"reporter":{"uuid":"{u1}"},
+				"links":{"html":{"href":"https://bitbucket.org/acme/platform/issues/7"}}}`, state)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceIssue().Schema, map[string]interface{}{
+		"workspace": "acme",
+		"repo_slug": "platform",
+		"title":     "Seed issue",
+		"content":   "Body",
+		"kind":      "task",
+		"priority":  "minor",
+		"component": "api",
+		"state":     "open",
+	})
+
+	if diags := resourceIssueCreate(context.Background(), d, Clients{httpClient: *client}); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if created.Component == nil || created.Component.Name != "api" || created.Milestone != nil || created.Assignee != nil {
+		t.Errorf("unexpected issue payload: %+v", created)
+	}
+	if change.Changes.State.New != "open" {
+		t.Errorf("state change = %q, want open", change.Changes.State.New)
+	}
+	if d.Id() != "acme/platform/7" || d.Get("state") != "open" || d.Get("component") != "api" || d.Get("milestone") != "" {
+		t.Errorf("unexpected state after create: id=%s state=%v component=%v milestone=%v", d.Id(), d.Get("state"), d.Get("component"), d.Get("milestone"))
+	}
+	if d.Get("url") != "https://bitbucket.org/acme/platform/issues/7" || d.Get("reporter") != "{u1}" {
+		t.Errorf("unexpected computed attributes: url=%v reporter=%v", d.Get("url"), d.Get("reporter"))
+	}
+}
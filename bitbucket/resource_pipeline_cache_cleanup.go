@@ -0,0 +1,187 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourcePipelineCacheCleanup() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourcePipelineCacheCleanupCreate,
+		ReadWithoutTimeout:   resourcePipelineCacheCleanupRead,
+		DeleteWithoutTimeout: resourcePipelineCacheCleanupDelete,
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"names": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "Names of the caches to delete",
+				AtLeastOneOf: []string{"names", "older_than_days", "all"},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+			"older_than_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "Delete caches created more than this many days ago",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"all": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ForceNew:      true,
+				Description:   "Delete all caches of the repository",
+				ConflictsWith: []string{"names", "older_than_days"},
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that, when changed, run the cleanup again",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"deleted_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of caches deleted",
+			},
+			"freed_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Combined size of the deleted caches in bytes",
+			},
+			"deleted_names": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Names of the deleted caches",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// pipelineCacheSelected reports whether the cleanup configured in d covers
+// cache. Names and age are combined, so both have to match when both are set.
+func pipelineCacheSelected(d *schema.ResourceData, cache *RepositoryPipelineCache, now time.Time) (bool, error) {
+	if d.Get("all").(bool) {
+		return true, nil
+	}
+
+	if v, ok := d.GetOk("names"); ok && !v.(*schema.Set).Contains(cache.Name) {
+		return false, nil
+	}
+
+	// nolint:staticcheck
+	if v, ok := d.GetOkExists("older_than_days"); ok {
+		created, err := time.Parse(time.RFC3339, cache.CreatedOn)
+		if err != nil {
+			return false, fmt.Errorf("unexpected creation time %q of cache %s: %w", cache.CreatedOn, cache.Name, err)
+		}
+		if !created.Before(now.AddDate(0, 0, -v.(int))) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func resourcePipelineCacheCleanupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+
+	_, byName := d.GetOk("names")
+	// nolint:staticcheck
+	_, byAge := d.GetOkExists("older_than_days")
+	if !byName && !byAge && !d.Get("all").(bool) {
+		return diag.Errorf("one of names, older_than_days or all = true is required to select the caches to delete")
+	}
+
+	values, err := client.GetPaginatedContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/pipelines-config/caches?pagelen=100", workspace, repoSlug))
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
+	now := time.Now()
+	deleted := 0
+	var freed int64
+	var names []interface{}
+	for _, raw := range values {
+		var cache RepositoryPipelineCache
+		if err := json.Unmarshal(raw, &cache); err != nil {
+			return diag.FromErr(err)
+		}
+
+		selected, err := pipelineCacheSelected(d, &cache, now)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if !selected {
+			continue
+		}
+
+		// Caches are deleted one by one, rather than by name, so only the
+		// selected ones go and the freed space is known.
+		_, err = client.DeleteContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/pipelines-config/caches/%s", workspace, repoSlug, cache.UUID))
+		if IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return diag.Errorf("error deleting pipeline cache %s of repository %s/%s: %s", cache.Name, workspace, repoSlug, err)
+		}
+		log.Printf("[DEBUG] Deleted pipeline cache %s (%s) of repository %s/%s", cache.Name, cache.UUID, workspace, repoSlug)
+
+		deleted++
+		freed += cache.bytes()
+		names = append(names, cache.Name)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", workspace, repoSlug, id.UniqueId()))
+	d.Set("deleted_count", deleted)
+	d.Set("freed_bytes", freed)
+	d.Set("deleted_names", names)
+
+	return nil
+}
+
+func resourcePipelineCacheCleanupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// The cleanup is a one-time action; there is nothing to read back.
+	return nil
+}
+
+func resourcePipelineCacheCleanupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Deleted caches cannot be restored; destroying the resource only
+	// removes it from state.
+	return nil
+}
@@ -0,0 +1,118 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccBitbucketPipelineCacheCleanup_basic(t *testing.T) {
+	resourceName := "bitbucket_pipeline_cache_cleanup.test"
+
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	//caches are only created by pipelines, so we are passing here a bootstrapped repo
+	repo := os.Getenv("BITBUCKET_PIPELINED_REPO")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckPipeSchedule(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketPipelineCacheCleanupConfig(workspace, repo, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "workspace", workspace),
+					resource.TestCheckResourceAttr(resourceName, "repo_slug", repo),
+					resource.TestCheckResourceAttrSet(resourceName, "deleted_count"),
+					resource.TestCheckResourceAttrSet(resourceName, "freed_bytes"),
+				),
+			},
+			{
+				// Nothing is left to delete once the first cleanup ran.
+				Config: testAccBitbucketPipelineCacheCleanupConfig(workspace, repo, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "deleted_count", "0"),
+					resource.TestCheckResourceAttr(resourceName, "freed_bytes", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketPipelineCacheCleanupConfig(workspace, repo, run string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_pipeline_cache_cleanup" "test" {
+  workspace = %[1]q
+  repo_slug = %[2]q
+  all       = true
+
+  triggers = {
+    run = %[3]q
+  }
+}
+`, workspace, repo, run)
+}
+
+func TestPipelineCacheCleanup(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -40).UTC().Format(time.RFC3339)
+	recent := time.Now().AddDate(0, 0, -2).UTC().Format(time.RFC3339)
+	caches := fmt.Sprintf(`{"values":[
+		{"uuid":"{c1}","name":"node","file_size_bytes":1000,"created_on":%q},
+		{"uuid":"{c2}","name":"node","file_size_bytes":300,"created_on":%q},
+		{"uuid":"{c3}","name":"gradle","file_size_bytes":5000,"created_on":%q}]}`, old, recent, old)
+
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		deleted []string
+		freed   int
+	}{
+		{"by name", map[string]interface{}{"names": []interface{}{"node"}}, []string{"{c1}", "{c2}"}, 1300},
+		{"by age", map[string]interface{}{"older_than_days": 30}, []string{"{c1}", "{c3}"}, 6000},
+		{"by name and age", map[string]interface{}{"names": []interface{}{"node"}, "older_than_days": 30}, []string{"{c1}"}, 1000},
+		{"all", map[string]interface{}{"all": true}, []string{"{c1}", "{c2}", "{c3}"}, 6300},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted []string
+			client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/2.0/repositories/acme/platform/pipelines-config/caches":
+					w.Write([]byte(caches))
+				case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/2.0/repositories/acme/platform/pipelines-config/caches/"):
+					deleted = append(deleted, path.Base(r.URL.Path))
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL)
+				}
+			})
+
+			raw := map[string]interface{}{"workspace": "acme", "repo_slug": "platform"}
+			for k, v := range tt.config {
+				raw[k] = v
+			}
+			d := schema.TestResourceDataRaw(t, resourcePipelineCacheCleanup().Schema, raw)
+
+			if diags := resourcePipelineCacheCleanupCreate(context.Background(), d, Clients{httpClient: *client}); diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if !reflect.DeepEqual(deleted, tt.deleted) {
+				t.Errorf("deleted %v, want %v", deleted, tt.deleted)
+			}
+			if d.Get("deleted_count") != len(tt.deleted) || d.Get("freed_bytes") != tt.freed {
+				t.Errorf("deleted_count=%v freed_bytes=%v", d.Get("deleted_count"), d.Get("freed_bytes"))
+			}
+		})
+	}
+}
@@ -0,0 +1,481 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// pipelineRunPollInterval is how often a triggered pipeline is checked while
+// waiting for it to complete.
+var pipelineRunPollInterval = 10 * time.Second
+
+func resourcePipelineRun() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourcePipelineRunCreate,
+		ReadWithoutTimeout:   resourcePipelineRunRead,
+		DeleteWithoutTimeout: resourcePipelineRunDelete,
+		CustomizeDiff:        validatePipelineRunTarget,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"branch": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				Description:   "Branch to run the pipeline on",
+				ConflictsWith: []string{"tag"},
+				AtLeastOneOf:  []string{"branch", "tag", "commit"},
+			},
+			"tag": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				Description:   "Tag to run the pipeline on",
+				ConflictsWith: []string{"branch"},
+			},
+			"commit": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Commit to run the pipeline on. Defaults to the head of `branch` or `tag`",
+			},
+			"selector": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Pipeline of bitbucket-pipelines.yml to run",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.StringInSlice([]string{"default", "branches", "tags", "custom", "pull-requests"}, false),
+						},
+						"pattern": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			"variable": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"value": {
+							Type:      schema.TypeString,
+							Required:  true,
+							ForceNew:  true,
+							Sensitive: true,
+						},
+						"secured": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							ForceNew: true,
+							Default:  false,
+						},
+					},
+				},
+			},
+			"wait_for_completion": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether the apply waits until the pipeline completes",
+			},
+			"fail_on_pipeline_failure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether the apply fails when the pipeline does not succeed. Implies `wait_for_completion`",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that, when changed, run the pipeline again",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"pipeline_uuid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"build_number": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "State of the pipeline: PENDING, IN_PROGRESS or COMPLETED",
+			},
+			"stage": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Stage of a pipeline in progress, such as RUNNING or PAUSED",
+			},
+			"result": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Result of a completed pipeline, such as SUCCESSFUL, FAILED, ERROR or STOPPED",
+			},
+			"steps": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"result": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// PipelineRunRequest is the body sent to trigger a pipeline.
+type PipelineRunRequest struct {
+	Target    PipelineRunTarget     `json:"target"`
+	Variables []PipelineRunVariable `json:"variables,omitempty"`
+}
+
+// PipelineRunTarget selects the revision and the pipeline to run.
+type PipelineRunTarget struct {
+	Type     string               `json:"type"`
+	RefType  string               `json:"ref_type,omitempty"`
+	RefName  string               `json:"ref_name,omitempty"`
+	Commit   *PipelineRunCommit   `json:"commit,omitempty"`
+	Selector *PipelineRunSelector `json:"selector,omitempty"`
+}
+
+type PipelineRunCommit struct {
+	Type string `json:"type"`
+	Hash string `json:"hash"`
+}
+
+type PipelineRunSelector struct {
+	Type    string `json:"type"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+type PipelineRunVariable struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Secured bool   `json:"secured"`
+}
+
+// PipelineRun is a triggered pipeline or one of its steps. Unlike the
+// pipelines data sources, the nested state objects are decoded in full.
+type PipelineRun struct {
+	UUID        string           `json:"uuid"`
+	Name        string           `json:"name"`
+	BuildNumber int              `json:"build_number"`
+	State       PipelineRunState `json:"state"`
+}
+
+type PipelineRunState struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Stage  *PipelineRunValue `json:"stage"`
+	Result *PipelineRunValue `json:"result"`
+}
+
+type PipelineRunValue struct {
+	Name string `json:"name"`
+}
+
+func (s *PipelineRunState) stage() string {
+	if s.Stage == nil {
+		return ""
+	}
+	return s.Stage.Name
+}
+
+func (s *PipelineRunState) result() string {
+	if s.Result == nil {
+		return ""
+	}
+	return s.Result.Name
+}
+
+func (s *PipelineRunState) completed() bool {
+	return s.Name == "COMPLETED"
+}
+
+// waiting reports whether the pipeline stopped making progress on its own:
+// it completed, or it is paused at a manual step or halted.
+func (s *PipelineRunState) waiting() bool {
+	switch {
+	case s.completed():
+		return true
+	case s.stage() == "PAUSED" || s.stage() == "HALTED":
+		return true
+	}
+	return false
+}
+
+func pipelineRunId(id string) (string, string, string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/PIPELINE-UUID", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// validatePipelineRunTarget rejects a commit target without a selector, as
+// Bitbucket only picks the pipeline from the branch or tag of a ref target.
+func validatePipelineRunTarget(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	for _, key := range []string{"branch", "tag", "selector"} {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	if d.Get("branch").(string) == "" && d.Get("tag").(string) == "" && len(d.Get("selector").([]interface{})) == 0 {
+		return fmt.Errorf("a selector is required to run a pipeline on a commit without a branch or tag")
+	}
+	return nil
+}
+
+func expandPipelineRunRequest(d *schema.ResourceData) *PipelineRunRequest {
+	target := PipelineRunTarget{Type: "pipeline_ref_target"}
+	if branch := d.Get("branch").(string); branch != "" {
+		target.RefType = "branch"
+		target.RefName = branch
+	} else if tag := d.Get("tag").(string); tag != "" {
+		target.RefType = "tag"
+		target.RefName = tag
+	} else {
+		target.Type = "pipeline_commit_target"
+	}
+
+	if commit := d.Get("commit").(string); commit != "" {
+		target.Commit = &PipelineRunCommit{Type: "commit", Hash: commit}
+	}
+
+	if v, ok := d.GetOk("selector"); ok {
+		selector := v.([]interface{})[0].(map[string]interface{})
+		target.Selector = &PipelineRunSelector{
+			Type:    selector["type"].(string),
+			Pattern: selector["pattern"].(string),
+		}
+	}
+
+	request := &PipelineRunRequest{Target: target}
+	for _, raw := range d.Get("variable").([]interface{}) {
+		variable := raw.(map[string]interface{})
+		request.Variables = append(request.Variables, PipelineRunVariable{
+			Key:     variable["key"].(string),
+			Value:   variable["value"].(string),
+			Secured: variable["secured"].(bool),
+		})
+	}
+
+	return request
+}
+
+func decodePipelineRun(body io.ReadCloser) (*PipelineRun, error) {
+	defer body.Close()
+
+	var run PipelineRun
+	if err := json.NewDecoder(body).Decode(&run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func resourcePipelineRunCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+
+	payload, err := json.Marshal(expandPipelineRunRequest(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := client.PostContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/pipelines/", workspace, repoSlug), bytes.NewBuffer(payload))
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+	run, err := decodePipelineRun(res.Body)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", workspace, repoSlug, run.UUID))
+	d.Set("pipeline_uuid", run.UUID)
+	log.Printf("[DEBUG] Triggered pipeline #%d (%s) in repository %s/%s", run.BuildNumber, run.UUID, workspace, repoSlug)
+
+	failOnFailure := d.Get("fail_on_pipeline_failure").(bool)
+	wait := d.Get("wait_for_completion").(bool) || failOnFailure
+	if wait {
+		// A pipeline paused at a manual step would otherwise block the apply
+		// until someone triggers the step.
+		run, err = waitForPipelineRun(ctx, &client, workspace, repoSlug, run, (*PipelineRunState).waiting)
+		if err != nil {
+			return diag.Errorf("error waiting for pipeline #%d in repository %s/%s: %s", run.BuildNumber, workspace, repoSlug, err)
+		}
+	}
+
+	if diags := resourcePipelineRunRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	// The pipeline has run, so it stays in state; as the error taints the
+	// resource, the next apply runs it again.
+	if !wait {
+		return nil
+	}
+	if run.State.completed() {
+		if failOnFailure && run.State.result() != "SUCCESSFUL" {
+			return diag.Errorf("pipeline #%d in repository %s/%s finished with result %s", run.BuildNumber, workspace, repoSlug, run.State.result())
+		}
+		return nil
+	}
+
+	// The pipeline is paused at a manual step or halted, and will not
+	// complete without someone stepping in.
+	if failOnFailure {
+		return diag.Errorf("pipeline #%d in repository %s/%s is %s and has not completed", run.BuildNumber, workspace, repoSlug, run.State.stage())
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Pipeline #%d has not completed", run.BuildNumber),
+		Detail:   fmt.Sprintf("The pipeline is %s and will not complete until a manual step is triggered or the pipeline is resumed.", run.State.stage()),
+	}}
+}
+
+// getPipelineRun fetches the pipeline with the given UUID.
+func getPipelineRun(ctx context.Context, client *Client, workspace, repoSlug, pipelineUUID string) (*PipelineRun, error) {
+	res, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/pipelines/%s", workspace, repoSlug, pipelineUUID))
+	if err != nil {
+		return nil, err
+	}
+	return decodePipelineRun(res.Body)
+}
+
+// waitForPipelineRun polls the pipeline until done reports true for its
+// state.
+func waitForPipelineRun(ctx context.Context, client *Client, workspace, repoSlug string, run *PipelineRun, done func(*PipelineRunState) bool) (*PipelineRun, error) {
+	for !done(&run.State) {
+		log.Printf("[DEBUG] Waiting for pipeline #%d: %s %s", run.BuildNumber, run.State.Name, run.State.stage())
+		if err := sleepContext(ctx, pipelineRunPollInterval); err != nil {
+			return run, fmt.Errorf("timeout while waiting for the pipeline: %w", err)
+		}
+
+		next, err := getPipelineRun(ctx, client, workspace, repoSlug, run.UUID)
+		if err != nil {
+			return run, err
+		}
+		run = next
+	}
+	return run, nil
+}
+
+func resourcePipelineRunRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, pipelineUUID, err := pipelineRunId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	run, err := getPipelineRun(ctx, &client, workspace, repoSlug, pipelineUUID)
+	if IsNotFound(err) {
+		log.Printf("[WARN] Pipeline Run (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	values, err := client.GetPaginatedContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/pipelines/%s/steps/?pagelen=100", workspace, repoSlug, pipelineUUID))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	steps := make([]interface{}, 0, len(values))
+	for _, raw := range values {
+		var step PipelineRun
+		if err := json.Unmarshal(raw, &step); err != nil {
+			return diag.FromErr(err)
+		}
+		steps = append(steps, map[string]interface{}{
+			"uuid":   step.UUID,
+			"name":   step.Name,
+			"state":  step.State.Name,
+			"result": step.State.result(),
+		})
+	}
+
+	d.Set("pipeline_uuid", run.UUID)
+	d.Set("build_number", run.BuildNumber)
+	d.Set("state", run.State.Name)
+	d.Set("stage", run.State.stage())
+	d.Set("result", run.State.result())
+	d.Set("steps", steps)
+
+	return nil
+}
+
+func resourcePipelineRunDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Pipelines cannot be deleted; destroying the resource leaves the run,
+	// and a pipeline that is still running keeps running.
+	return nil
+}
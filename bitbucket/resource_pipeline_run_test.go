@@ -0,0 +1,195 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketPipelineRun_basic(t *testing.T) {
+	resourceName := "bitbucket_pipeline_run.test"
+
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	//running a pipeline requires a bitbucket-pipelines.yml, so we are passing here a bootstrapped repo
+	repo := os.Getenv("BITBUCKET_PIPELINED_REPO")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckPipeSchedule(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketPipelineRunConfig(workspace, repo, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "workspace", workspace),
+					resource.TestCheckResourceAttr(resourceName, "repo_slug", repo),
+					resource.TestCheckResourceAttr(resourceName, "state", "COMPLETED"),
+					resource.TestCheckResourceAttr(resourceName, "result", "SUCCESSFUL"),
+					resource.TestCheckResourceAttrSet(resourceName, "pipeline_uuid"),
+					resource.TestCheckResourceAttrSet(resourceName, "build_number"),
+				),
+			},
+			{
+				Config: testAccBitbucketPipelineRunConfig(workspace, repo, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "triggers.run", "second"),
+					resource.TestCheckResourceAttr(resourceName, "state", "COMPLETED"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketPipelineRunConfig(workspace, repo, run string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_pipeline_run" "test" {
+  workspace                = %[1]q
+  repo_slug                = %[2]q
+  branch                   = "master"
+  fail_on_pipeline_failure = true
+
+  triggers = {
+    run = %[3]q
+  }
+}
+`, workspace, repo, run)
+}
+
+func TestPipelineRunWaitsAndFailsOnFailure(t *testing.T) {
+	defer func(interval time.Duration) { pipelineRunPollInterval = interval }(pipelineRunPollInterval)
+	pipelineRunPollInterval = time.Millisecond
+
+	var request PipelineRunRequest
+	polls := 0
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/2.0/repositories/acme/platform/pipelines/":
+			json.NewDecoder(r.Body).Decode(&request)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"uuid":"{p1}","build_number":42,"state":{"name":"PENDING"}}`))
+		case r.URL.Path == "/2.0/repositories/acme/platform/pipelines/{p1}":
+			polls++
+			if polls < 3 {
+				w.Write([]byte(`{"uuid":"{p1}","build_number":42,"state":{"name":"IN_PROGRESS","stage":{"name":"RUNNING"}}}`))
+				return
+			}
+			w.Write([]byte(`{"uuid":"{p1}","build_number":42,"state":{"name":"COMPLETED","result":{"name":"FAILED"}}}`))
+		case r.URL.Path == "/2.0/repositories/acme/platform/pipelines/{p1}/steps/":
+			w.Write([]byte(`{"values":[
+				{"uuid":"{s1}","name":"Build","state":{"name":"COMPLETED","result":{"name":"SUCCESSFUL"}}},
+				{"uuid":"{s2}","name":"Bootstrap","state":{"name":"COMPLETED","result":{"name":"FAILED"}}}]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourcePipelineRun().Schema, map[string]interface{}{
+		"workspace":                "acme",
+		"repo_slug":                "platform",
+		"branch":                   "main",
+		"selector":                 []interface{}{map[string]interface{}{"type": "custom", "pattern": "bootstrap"}},
+		"variable":                 []interface{}{map[string]interface{}{"key": "TOKEN", "value": "s3cr3t", "secured": true}},
+		"fail_on_pipeline_failure": true,
+	})
+
+	diags := resourcePipelineRunCreate(context.Background(), d, Clients{httpClient: *client})
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "finished with result FAILED") {
+		t.Fatalf("expected a pipeline failure, got %v", diags)
+	}
+
+	target := request.Target
+	if target.Type != "pipeline_ref_target" || target.RefType != "branch" || target.RefName != "main" || target.Commit != nil {
+		t.Errorf("unexpected target: %+v", target)
+	}
+	if target.Selector == nil || target.Selector.Type != "custom" || target.Selector.Pattern != "bootstrap" {
+		t.Errorf("unexpected selector: %+v", target.Selector)
+	}
+	if len(request.Variables) != 1 || request.Variables[0] != (PipelineRunVariable{Key: "TOKEN", Value: "s3cr3t", Secured: true}) {
+		t.Errorf("unexpected variables: %+v", request.Variables)
+	}
+
+	// The run stays in state so the next apply replaces the tainted resource.
+	if d.Id() != "acme/platform/{p1}" || d.Get("build_number") != 42 || d.Get("state") != "COMPLETED" || d.Get("result") != "FAILED" {
+		t.Errorf("unexpected state: id=%s build_number=%v state=%v result=%v", d.Id(), d.Get("build_number"), d.Get("state"), d.Get("result"))
+	}
+	if d.Get("steps.#") != 2 || d.Get("steps.1.name") != "Bootstrap" || d.Get("steps.1.result") != "FAILED" {
+		t.Errorf("unexpected steps: %v", d.Get("steps"))
+	}
+}
+
+func TestPipelineRunCommitTarget(t *testing.T) {
+	r := resourcePipelineRun()
+	config := map[string]interface{}{
+		"workspace": "acme",
+		"repo_slug": "platform",
+		"commit":    "abc123",
+	}
+	if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil); err == nil || !strings.Contains(err.Error(), "a selector is required") {
+		t.Errorf("expected the plan to reject a commit target without a selector, got %v", err)
+	}
+
+	config["selector"] = []interface{}{map[string]interface{}{"type": "custom", "pattern": "release"}}
+	if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	request := expandPipelineRunRequest(schema.TestResourceDataRaw(t, r.Schema, config))
+	if request.Target.Type != "pipeline_commit_target" || request.Target.RefType != "" || request.Target.Commit == nil || request.Target.Commit.Hash != "abc123" {
+		t.Errorf("unexpected target: %+v", request.Target)
+	}
+}
+
+func TestPipelineRunPaused(t *testing.T) {
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/2.0/repositories/acme/platform/pipelines/":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"uuid":"{p1}","build_number":7,"state":{"name":"IN_PROGRESS","stage":{"name":"PAUSED"}}}`))
+		case r.URL.Path == "/2.0/repositories/acme/platform/pipelines/{p1}":
+			w.Write([]byte(`{"uuid":"{p1}","build_number":7,"state":{"name":"IN_PROGRESS","stage":{"name":"PAUSED"}}}`))
+		case r.URL.Path == "/2.0/repositories/acme/platform/pipelines/{p1}/steps/":
+			w.Write([]byte(`{"values":[]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	// A pipeline paused at a manual step has not succeeded.
+	d := schema.TestResourceDataRaw(t, resourcePipelineRun().Schema, map[string]interface{}{
+		"workspace":                "acme",
+		"repo_slug":                "platform",
+		"branch":                   "main",
+		"fail_on_pipeline_failure": true,
+	})
+	diags := resourcePipelineRunCreate(context.Background(), d, Clients{httpClient: *client})
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "is PAUSED and has not completed") {
+		t.Errorf("expected a paused pipeline to fail the apply, got %v", diags)
+	}
+	if d.Id() != "acme/platform/{p1}" || d.Get("stage") != "PAUSED" {
+		t.Errorf("unexpected state: id=%s stage=%v", d.Id(), d.Get("stage"))
+	}
+
+	// Only waiting for the pipeline warns instead.
+	d = schema.TestResourceDataRaw(t, resourcePipelineRun().Schema, map[string]interface{}{
+		"workspace":           "acme",
+		"repo_slug":           "platform",
+		"branch":              "main",
+		"wait_for_completion": true,
+	})
+	diags = resourcePipelineRunCreate(context.Background(), d, Clients{httpClient: *client})
+	if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Errorf("expected a warning, got %v", diags)
+	}
+}
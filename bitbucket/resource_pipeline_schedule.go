@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -102,18 +101,18 @@ func resourcePipelineScheduleCreate(ctx context.Context, d *schema.ResourceData,
 
 	repo := d.Get("repository").(string)
 	workspace := d.Get("workspace").(string)
-	schedule, res, err := pipeApi.CreateRepositoryPipelineSchedule(c.AuthContext, *pipeSchedule, workspace, repo)
+	schedule, res, err := pipeApi.CreateRepositoryPipelineSchedule(c.requestContext(ctx), *pipeSchedule, workspace, repo)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s/%s", workspace, repo, schedule.Uuid))
 
 	if !d.Get("enabled").(bool) {
 		pipeScheduleUpdate := expandUpdatePipelineSchedule(d)
-		_, res, err = pipeApi.UpdateRepositoryPipelineSchedule(c.AuthContext, *pipeScheduleUpdate, workspace, repo, schedule.Uuid)
+		_, res, err = pipeApi.UpdateRepositoryPipelineSchedule(c.requestContext(ctx), *pipeScheduleUpdate, workspace, repo, schedule.Uuid)
 		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 
@@ -131,9 +130,9 @@ func resourcePipelineScheduleUpdate(ctx context.Context, d *schema.ResourceData,
 
 	pipeScheduleUpdate := expandUpdatePipelineSchedule(d)
 	log.Printf("[DEBUG] Pipeline Schedule Request: %#v", pipeScheduleUpdate)
-	_, res, err := pipeApi.UpdateRepositoryPipelineSchedule(c.AuthContext, *pipeScheduleUpdate, workspace, repo, uuid)
+	_, res, err := pipeApi.UpdateRepositoryPipelineSchedule(c.requestContext(ctx), *pipeScheduleUpdate, workspace, repo, uuid)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourcePipelineScheduleRead(ctx, d, m)
@@ -148,16 +147,16 @@ func resourcePipelineScheduleRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	schedule, res, err := pipeApi.GetRepositoryPipelineSchedule(c.AuthContext, workspace, repo, uuid)
+	schedule, res, err := pipeApi.GetRepositoryPipelineSchedule(c.requestContext(ctx), workspace, repo, uuid)
 
-	if res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Pipeline Schedule (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.Set("repository", repo)
@@ -179,9 +178,9 @@ func resourcePipelineScheduleDelete(ctx context.Context, d *schema.ResourceData,
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	res, err := pipeApi.DeleteRepositoryPipelineSchedule(c.AuthContext, workspace, repo, uuid)
+	res, err := pipeApi.DeleteRepositoryPipelineSchedule(c.requestContext(ctx), workspace, repo, uuid)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return diag.FromErr(err)
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -54,9 +53,9 @@ func resourcePipelineSshKeysPut(ctx context.Context, d *schema.ResourceData, m i
 
 	repo := d.Get("repository").(string)
 	workspace := d.Get("workspace").(string)
-	_, res, err := pipeApi.UpdateRepositoryPipelineKeyPair(c.AuthContext, *pipeSshKey, workspace, repo)
+	_, res, err := pipeApi.UpdateRepositoryPipelineKeyPair(c.requestContext(ctx), *pipeSshKey, workspace, repo)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s", workspace, repo))
@@ -73,16 +72,16 @@ func resourcePipelineSshKeysRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	key, res, err := pipeApi.GetRepositoryPipelineSshKeyPair(c.AuthContext, workspace, repo)
+	key, res, err := pipeApi.GetRepositoryPipelineSshKeyPair(c.requestContext(ctx), workspace, repo)
 
-	if res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Pipeline Ssh Key (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.Set("repository", repo)
@@ -102,9 +101,9 @@ func resourcePipelineSshKeysDelete(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	res, err := pipeApi.DeleteRepositoryPipelineKeyPair(c.AuthContext, workspace, repo)
+	res, err := pipeApi.DeleteRepositoryPipelineKeyPair(c.requestContext(ctx), workspace, repo)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return diag.FromErr(err)
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -81,9 +80,9 @@ func resourcePipelineSshKnownHostsCreate(ctx context.Context, d *schema.Resource
 
 	repo := d.Get("repository").(string)
 	workspace := d.Get("workspace").(string)
-	host, res, err := pipeApi.CreateRepositoryPipelineKnownHost(c.AuthContext, *pipeSshKnownHost, workspace, repo)
+	host, res, err := pipeApi.CreateRepositoryPipelineKnownHost(c.requestContext(ctx), *pipeSshKnownHost, workspace, repo)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s/%s", workspace, repo, host.Uuid))
@@ -102,9 +101,9 @@ func resourcePipelineSshKnownHostsUpdate(ctx context.Context, d *schema.Resource
 
 	pipeSshKnownHost := expandPipelineSshKnownHost(d)
 	log.Printf("[DEBUG] Pipeline Ssh Key Request: %#v", pipeSshKnownHost)
-	_, res, err := pipeApi.UpdateRepositoryPipelineKnownHost(c.AuthContext, *pipeSshKnownHost, workspace, repo, uuid)
+	_, res, err := pipeApi.UpdateRepositoryPipelineKnownHost(c.requestContext(ctx), *pipeSshKnownHost, workspace, repo, uuid)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourcePipelineSshKnownHostsRead(ctx, d, m)
@@ -119,16 +118,16 @@ func resourcePipelineSshKnownHostsRead(ctx context.Context, d *schema.ResourceDa
 		return diag.FromErr(err)
 	}
 
-	host, res, err := pipeApi.GetRepositoryPipelineKnownHost(c.AuthContext, workspace, repo, uuid)
+	host, res, err := pipeApi.GetRepositoryPipelineKnownHost(c.requestContext(ctx), workspace, repo, uuid)
 
-	if res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Pipeline Ssh known host (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.Set("repository", repo)
@@ -148,9 +147,9 @@ func resourcePipelineSshKnownHostsDelete(ctx context.Context, d *schema.Resource
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	res, err := pipeApi.DeleteRepositoryPipelineKnownHost(c.AuthContext, workspace, repo, uuid)
+	res, err := pipeApi.DeleteRepositoryPipelineKnownHost(c.requestContext(ctx), workspace, repo, uuid)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return diag.FromErr(err)
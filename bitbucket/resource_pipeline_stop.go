@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -13,12 +13,26 @@ import (
 
 func resourcePipelineStop() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: resourcePipelineStopCreate,
-		ReadContext:   resourcePipelineStopRead,
-		DeleteContext: resourcePipelineStopDelete,
+		CreateWithoutTimeout: resourcePipelineStopCreate,
+		ReadWithoutTimeout:   resourcePipelineStopRead,
+		DeleteWithoutTimeout: resourcePipelineStopDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				workspace, repoSlug, pipelineUUID, err := pipelineRunId(d.Id())
+				if err != nil {
+					return nil, err
+				}
+				d.Set("workspace", workspace)
+				d.Set("repo_slug", repoSlug)
+				d.Set("pipeline_uuid", pipelineUUID)
+				d.Set("wait", true)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
 		},
+
 		Schema: map[string]*schema.Schema{
 			"workspace": {
 				Type:         schema.TypeString,
@@ -41,10 +55,30 @@ func resourcePipelineStop() *schema.Resource {
 				Description:  "Pipeline UUID",
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
+			"wait": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Whether the apply waits until the pipeline has stopped",
+			},
 			"stopped": {
 				Type:        schema.TypeBool,
 				Computed:    true,
-				Description: "Whether the pipeline was successfully stopped",
+				Description: "Whether the pipeline completed with the STOPPED result",
+			},
+			"already_completed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the pipeline had already completed when the stop was requested",
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"result": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 		},
 	}
@@ -57,37 +91,81 @@ func resourcePipelineStopCreate(ctx context.Context, d *schema.ResourceData, m i
 	repoSlug := d.Get("repo_slug").(string)
 	pipelineUUID := d.Get("pipeline_uuid").(string)
 
-	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/pipelines/%s/stopPipeline", workspace, repoSlug, pipelineUUID)
-	res, err := client.Post(endpoint, nil)
+	run, err := getPipelineRun(ctx, &client, workspace, repoSlug, pipelineUUID)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
-	if res == nil {
-		return diag.Errorf("no response returned from pipeline stop")
-	}
-
-	if res.StatusCode != http.StatusNoContent {
-		return diag.Errorf("failed to stop pipeline: status %d", res.StatusCode)
+	if !run.State.completed() {
+		endpoint := fmt.Sprintf("2.0/repositories/%s/%s/pipelines/%s/stopPipeline", workspace, repoSlug, pipelineUUID)
+		if _, err := client.PostContext(ctx, endpoint, nil); err != nil {
+			// The pipeline may have completed since it was fetched, in
+			// which case Bitbucket refuses to stop it.
+			current, getErr := getPipelineRun(ctx, &client, workspace, repoSlug, pipelineUUID)
+			if getErr != nil || !current.State.completed() {
+				return apiErrorDiagnostics(err, d)
+			}
+			run = current
+		} else {
+			log.Printf("[DEBUG] Requested stop of pipeline #%d (%s) in repository %s/%s", run.BuildNumber, pipelineUUID, workspace, repoSlug)
+		}
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s/%s", workspace, repoSlug, pipelineUUID))
-	d.Set("stopped", true)
 
-	log.Printf("[DEBUG] Stopped pipeline: %s in repository %s/%s", pipelineUUID, workspace, repoSlug)
+	var diags diag.Diagnostics
+	if run.State.completed() {
+		d.Set("already_completed", true)
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Pipeline #%d had already completed", run.BuildNumber),
+			Detail:   fmt.Sprintf("The pipeline completed with result %s before it could be stopped.", run.State.result()),
+		})
+	} else {
+		d.Set("already_completed", false)
+		if d.Get("wait").(bool) {
+			if _, err := waitForPipelineRun(ctx, &client, workspace, repoSlug, run, (*PipelineRunState).completed); err != nil {
+				return diag.Errorf("error waiting for pipeline #%d in repository %s/%s to stop: %s", run.BuildNumber, workspace, repoSlug, err)
+			}
+		}
+	}
 
-	return nil
+	return append(diags, resourcePipelineStopRead(ctx, d, m)...)
 }
 
 func resourcePipelineStopRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Pipeline stop is a one-time action, so we don't need to read state
-	// Just return the current state
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, pipelineUUID, err := pipelineRunId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	run, err := getPipelineRun(ctx, &client, workspace, repoSlug, pipelineUUID)
+	if IsNotFound(err) {
+		log.Printf("[WARN] Pipeline (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// State written before wait existed has no value for it, which would
+	// otherwise show up as a change to the default and replace the resource.
+	// nolint:staticcheck
+	if _, ok := d.GetOkExists("wait"); !ok {
+		d.Set("wait", true)
+	}
+	d.Set("state", run.State.Name)
+	d.Set("result", run.State.result())
+	d.Set("stopped", run.State.completed() && run.State.result() == "STOPPED")
+
 	return nil
 }
 
 func resourcePipelineStopDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Pipeline stop is a one-time action, so deletion is a no-op
-	// Just remove from state
-	d.SetId("")
+	// Stopping a pipeline cannot be undone; destroying the resource only
+	// removes it from state.
 	return nil
 }
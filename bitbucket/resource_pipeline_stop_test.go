@@ -0,0 +1,181 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketPipelineStop_basic(t *testing.T) {
+	resourceName := "bitbucket_pipeline_stop.test"
+
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	//stopping a pipeline requires one to run, so we are passing here a bootstrapped repo
+	repo := os.Getenv("BITBUCKET_PIPELINED_REPO")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckPipeSchedule(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketPipelineStopConfig(workspace, repo),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "pipeline_uuid", "bitbucket_pipeline_run.test", "pipeline_uuid"),
+					resource.TestCheckResourceAttr(resourceName, "state", "COMPLETED"),
+					resource.TestCheckResourceAttrSet(resourceName, "result"),
+					resource.TestCheckResourceAttrSet(resourceName, "stopped"),
+					resource.TestCheckResourceAttrSet(resourceName, "already_completed"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"already_completed"},
+			},
+		},
+	})
+}
+
+func testAccBitbucketPipelineStopConfig(workspace, repo string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_pipeline_run" "test" {
+  workspace = %[1]q
+  repo_slug = %[2]q
+  branch    = "master"
+}
+
+resource "bitbucket_pipeline_stop" "test" {
+  workspace     = bitbucket_pipeline_run.test.workspace
+  repo_slug     = bitbucket_pipeline_run.test.repo_slug
+  pipeline_uuid = bitbucket_pipeline_run.test.pipeline_uuid
+}
+`, workspace, repo)
+}
+
+func TestPipelineStopWaitsForTermination(t *testing.T) {
+	defer func(interval time.Duration) { pipelineRunPollInterval = interval }(pipelineRunPollInterval)
+	pipelineRunPollInterval = time.Millisecond
+
+	stopped := false
+	polls := 0
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/2.0/repositories/acme/platform/pipelines/{p1}/stopPipeline":
+			stopped = true
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/2.0/repositories/acme/platform/pipelines/{p1}":
+			if stopped {
+				polls++
+			}
+			if polls < 3 {
+				w.Write([]byte(`{"uuid":"{p1}","build_number":7,"state":{"name":"IN_PROGRESS","stage":{"name":"RUNNING"}}}`))
+				return
+			}
+			w.Write([]byte(`{"uuid":"{p1}","build_number":7,"state":{"name":"COMPLETED","result":{"name":"STOPPED"}}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourcePipelineStop().Schema, map[string]interface{}{
+		"workspace":     "acme",
+		"repo_slug":     "platform",
+		"pipeline_uuid": "{p1}",
+	})
+
+	if diags := resourcePipelineStopCreate(context.Background(), d, Clients{httpClient: *client}); diags.HasError() || len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !stopped || polls < 3 {
+		t.Errorf("stop requested=%v, polled %d times", stopped, polls)
+	}
+	if d.Id() != "acme/platform/{p1}" || !d.Get("stopped").(bool) || d.Get("already_completed").(bool) || d.Get("result") != "STOPPED" {
+		t.Errorf("unexpected state: id=%s stopped=%v already_completed=%v result=%v", d.Id(), d.Get("stopped"), d.Get("already_completed"), d.Get("result"))
+	}
+}
+
+func TestPipelineStopAlreadyCompleted(t *testing.T) {
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/2.0/repositories/acme/platform/pipelines/{p1}" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			return
+		}
+		w.Write([]byte(`{"uuid":"{p1}","build_number":7,"state":{"name":"COMPLETED","result":{"name":"SUCCESSFUL"}}}`))
+	})
+
+	d := schema.TestResourceDataRaw(t, resourcePipelineStop().Schema, map[string]interface{}{
+		"workspace":     "acme",
+		"repo_slug":     "platform",
+		"pipeline_uuid": "{p1}",
+	})
+
+	diags := resourcePipelineStopCreate(context.Background(), d, Clients{httpClient: *client})
+	if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a single warning, got %v", diags)
+	}
+	if d.Get("stopped").(bool) || !d.Get("already_completed").(bool) || d.Get("result") != "SUCCESSFUL" {
+		t.Errorf("unexpected state: stopped=%v already_completed=%v result=%v", d.Get("stopped"), d.Get("already_completed"), d.Get("result"))
+	}
+}
+
+func TestPipelineStopUpgradeKeepsResource(t *testing.T) {
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/2.0/repositories/acme/platform/pipelines/{p1}" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		w.Write([]byte(`{"uuid":"{p1}","build_number":7,"state":{"name":"COMPLETED","result":{"name":"STOPPED"}}}`))
+	})
+	meta := Clients{httpClient: *client}
+	r := resourcePipelineStop()
+	ctx := context.Background()
+
+	// State written by an earlier version of the provider.
+	state := &terraform.InstanceState{
+		ID: "acme/platform/{p1}",
+		Attributes: map[string]string{
+			"id":            "acme/platform/{p1}",
+			"workspace":     "acme",
+			"repo_slug":     "platform",
+			"pipeline_uuid": "{p1}",
+			"stopped":       "true",
+		},
+	}
+	state, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if state.Attributes["wait"] != "true" {
+		t.Errorf("wait = %q, want true", state.Attributes["wait"])
+	}
+
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"workspace":     "acme",
+		"repo_slug":     "platform",
+		"pipeline_uuid": "{p1}",
+	}), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Errorf("unexpected diff after upgrade: %v", diff)
+	}
+
+	// An explicit wait = false is kept.
+	state.Attributes["wait"] = "false"
+	state, diags = r.RefreshWithoutUpgrade(ctx, state, meta)
+	if diags.HasError() || state.Attributes["wait"] != "false" {
+		t.Errorf("wait = %q, diagnostics %v", state.Attributes["wait"], diags)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -121,9 +120,9 @@ func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, m interf
 	log.Printf("[DEBUG] Project Update Body: %#v", project)
 	project.Links = nil
 
-	prj, res, err := projectApi.WorkspacesWorkspaceProjectsProjectKeyPut(c.AuthContext, *project, projectKey, d.Get("owner").(string))
+	prj, res, err := projectApi.WorkspacesWorkspaceProjectsProjectKeyPut(c.requestContext(ctx), *project, projectKey, d.Get("owner").(string))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	log.Printf("[DEBUG] Project Update Res: %#v", prj)
@@ -142,7 +141,7 @@ func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, m interf
 
 	// 		log.Printf("[DEBUG] Project Update Links Body: %s", string(payload))
 
-	// 		_, err = client.Put(fmt.Sprintf("2.0/workspaces/%s/projects/%s",
+	// 		_, err = client.PutContext(ctx, fmt.Sprintf("2.0/workspaces/%s/projects/%s",
 	// 			d.Get("owner").(string), d.Get("key").(string),
 	// 		), bytes.NewBuffer(payload))
 
@@ -165,9 +164,9 @@ func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 	log.Printf("[DEBUG] Project Create Body: %#v", project)
 
-	projRes, res, err := projectApi.WorkspacesWorkspaceProjectsPost(c.AuthContext, *project, owner)
+	projRes, res, err := projectApi.WorkspacesWorkspaceProjectsPost(c.requestContext(ctx), *project, owner)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s", owner, projRes.Key))
@@ -196,16 +195,16 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interfac
 	c := m.(Clients).genClient
 	projectApi := c.ApiClient.ProjectsApi
 
-	projRes, res, err := projectApi.WorkspacesWorkspaceProjectsProjectKeyGet(c.AuthContext, projectKey, d.Get("owner").(string))
+	projRes, res, err := projectApi.WorkspacesWorkspaceProjectsProjectKeyGet(c.requestContext(ctx), projectKey, d.Get("owner").(string))
 
-	if res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Project (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.Set("key", projRes.Key)
@@ -230,9 +229,9 @@ func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, m interf
 	c := m.(Clients).genClient
 	projectApi := c.ApiClient.ProjectsApi
 
-	res, err := projectApi.WorkspacesWorkspaceProjectsProjectKeyDelete(c.AuthContext, projectKey, d.Get("owner").(string))
+	res, err := projectApi.WorkspacesWorkspaceProjectsProjectKeyDelete(c.requestContext(ctx), projectKey, d.Get("owner").(string))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return nil
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -129,13 +128,13 @@ func resourceProjectBranchingModelsPut(ctx context.Context, d *schema.ResourceDa
 		return diag.FromErr(err)
 	}
 
-	branchingModelReq, err := client.Put(fmt.Sprintf("2.0/workspaces/%s/projects/%s/branching-model/settings",
+	branchingModelReq, err := client.PutContext(ctx, fmt.Sprintf("2.0/workspaces/%s/projects/%s/branching-model/settings",
 		d.Get("workspace").(string),
 		d.Get("project").(string),
 	), bytes.NewBuffer(bytedata))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(branchingModelReq.Body)
@@ -160,14 +159,18 @@ func resourceProjectBranchingModelsRead(ctx context.Context, d *schema.ResourceD
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	branchingModelsReq, _ := client.Get(fmt.Sprintf("2.0/workspaces/%s/projects/%s/branching-model/settings", workspace, repo))
+	branchingModelsReq, err := client.GetContext(ctx, fmt.Sprintf("2.0/workspaces/%s/projects/%s/branching-model/settings", workspace, repo))
 
-	if branchingModelsReq.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Project Branching Model (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	if branchingModelsReq.Body == nil {
 		return diag.Errorf("error getting Project Branching Model (%s): empty response", d.Id())
 	}
@@ -204,10 +207,10 @@ func resourceProjectBranchingModelsDelete(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Put(fmt.Sprintf("2.0/workspaces/%s/projects/%s/branching-model/settings", workspace, repo), nil)
+	_, err = client.PutContext(ctx, fmt.Sprintf("2.0/workspaces/%s/projects/%s/branching-model/settings", workspace, repo), nil)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return diag.FromErr(err)
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -52,9 +51,9 @@ func resourceProjectDefaultReviewersCreate(ctx context.Context, d *schema.Resour
 
 	for _, user := range d.Get("reviewers").(*schema.Set).List() {
 		userName := user.(string)
-		_, res, err := projectsApi.WorkspacesWorkspaceProjectsProjectKeyDefaultReviewersSelectedUserPut(c.AuthContext, project, userName, workspace)
+		_, res, err := projectsApi.WorkspacesWorkspaceProjectsProjectKeyDefaultReviewersSelectedUserPut(c.requestContext(ctx), project, userName, workspace)
 		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 
@@ -75,17 +74,17 @@ func resourceProjectDefaultReviewersRead(ctx context.Context, d *schema.Resource
 	var terraformReviewers []string
 
 	for {
-		reviewers, res, err := projectsApi.WorkspacesWorkspaceProjectsProjectKeyDefaultReviewersGet(c.AuthContext, project, workspace, &options)
-		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
-		}
-
-		if res.StatusCode == http.StatusNotFound {
+		reviewers, res, err := projectsApi.WorkspacesWorkspaceProjectsProjectKeyDefaultReviewersGet(c.requestContext(ctx), project, workspace, &options)
+		if IsNotFound(err) {
 			log.Printf("[WARN] Project Default Reviewers (%s) not found, removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 
+		if err := handleClientError(res, err); err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+
 		for _, reviewer := range reviewers.Values {
 			terraformReviewers = append(terraformReviewers, reviewer.User.Uuid)
 		}
@@ -120,17 +119,17 @@ func resourceProjectDefaultReviewersUpdate(ctx context.Context, d *schema.Resour
 
 	for _, user := range add.List() {
 		userName := user.(string)
-		_, res, err := projectsApi.WorkspacesWorkspaceProjectsProjectKeyDefaultReviewersSelectedUserPut(c.AuthContext, project, userName, workspace)
+		_, res, err := projectsApi.WorkspacesWorkspaceProjectsProjectKeyDefaultReviewersSelectedUserPut(c.requestContext(ctx), project, userName, workspace)
 		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 
 	for _, user := range remove.List() {
 		userName := user.(string)
-		res, err := projectsApi.WorkspacesWorkspaceProjectsProjectKeyDefaultReviewersSelectedUserDelete(c.AuthContext, project, userName, workspace)
+		res, err := projectsApi.WorkspacesWorkspaceProjectsProjectKeyDefaultReviewersSelectedUserDelete(c.requestContext(ctx), project, userName, workspace)
 		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 
@@ -145,9 +144,9 @@ func resourceProjectDefaultReviewersDelete(ctx context.Context, d *schema.Resour
 	workspace := d.Get("workspace").(string)
 	for _, user := range d.Get("reviewers").(*schema.Set).List() {
 		userName := user.(string)
-		res, err := projectsApi.WorkspacesWorkspaceProjectsProjectKeyDefaultReviewersSelectedUserDelete(c.AuthContext, project, userName, workspace)
+		res, err := projectsApi.WorkspacesWorkspaceProjectsProjectKeyDefaultReviewersSelectedUserDelete(c.requestContext(ctx), project, userName, workspace)
 		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 	return nil
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -103,9 +102,9 @@ func resourceProjectDeployKeyCreate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	url := fmt.Sprintf("2.0/workspaces/%s/projects/%s/deploy-keys", workspace, projectKey)
-	res, err := client.Post(url, bytes.NewBuffer(payload))
+	res, err := client.PostContext(ctx, url, bytes.NewBuffer(payload))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(res.Body)
@@ -134,21 +133,21 @@ func resourceProjectDeployKeyRead(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	url := fmt.Sprintf("2.0/workspaces/%s/projects/%s/deploy-keys/%s", workspace, projectKey, keyID)
-	res, err := client.Get(url)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	if res.StatusCode == http.StatusNotFound {
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
 		log.Printf("[WARN] Project Deploy Key (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	if err := handleClientError(res, err); err != nil {
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if err := handleClientError(res, err); err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
 	body, readerr := io.ReadAll(res.Body)
 	if readerr != nil {
 		return diag.FromErr(readerr)
@@ -182,9 +181,9 @@ func resourceProjectDeployKeyDelete(ctx context.Context, d *schema.ResourceData,
 	}
 
 	url := fmt.Sprintf("2.0/workspaces/%s/projects/%s/deploy-keys/%s", workspace, projectKey, keyID)
-	res, err := client.Delete(url)
+	res, err := client.DeleteContext(ctx, url)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return nil
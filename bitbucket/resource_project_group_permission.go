@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -84,14 +83,14 @@ func resourceProjectGroupPermissionPut(ctx context.Context, d *schema.ResourceDa
 	projectKey := d.Get("project_key").(string)
 	groupSlug := d.Get("group_slug").(string)
 
-	permissionReq, err := client.Put(fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/groups/%s",
+	permissionReq, err := client.PutContext(ctx, fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/groups/%s",
 		workspace,
 		projectKey,
 		groupSlug,
 	), bytes.NewBuffer(payload))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(permissionReq.Body)
@@ -119,13 +118,13 @@ func resourceProjectGroupPermissionRead(ctx context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 
-	permissionReq, err := client.Get(fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/groups/%s",
+	permissionReq, err := client.GetContext(ctx, fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/groups/%s",
 		workspace,
 		projectKey,
 		groupSlug,
 	))
 
-	if permissionReq.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Project Group Permission (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -167,7 +166,7 @@ func resourceProjectGroupPermissionDelete(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Delete(fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/groups/%s",
+	_, err = client.DeleteContext(ctx, fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/groups/%s",
 		workspace,
 		projectKey,
 		groupSlug,
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -81,14 +80,14 @@ func resourceProjectUserPermissionPut(ctx context.Context, d *schema.ResourceDat
 	projectKey := d.Get("project_key").(string)
 	userSlug := d.Get("user_id").(string)
 
-	permissionReq, err := client.Put(fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/users/%s",
+	permissionReq, err := client.PutContext(ctx, fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/users/%s",
 		workspace,
 		projectKey,
 		userSlug,
 	), bytes.NewBuffer(payload))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(permissionReq.Body)
@@ -116,13 +115,13 @@ func resourceProjectUserPermissionRead(ctx context.Context, d *schema.ResourceDa
 		return diag.FromErr(err)
 	}
 
-	permissionReq, err := client.Get(fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/users/%s",
+	permissionReq, err := client.GetContext(ctx, fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/users/%s",
 		workspace,
 		projectKey,
 		userSlug,
 	))
 
-	if permissionReq.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Project User Permission (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -164,7 +163,7 @@ func resourceProjectUserPermissionDelete(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Delete(fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/users/%s",
+	_, err = client.DeleteContext(ctx, fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config/users/%s",
 		workspace,
 		projectKey,
 		userSlug,
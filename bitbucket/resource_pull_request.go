@@ -0,0 +1,491 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DrFaust92/bitbucket-go-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// pullRequestMergePollInterval is how often the status of an asynchronous
+// merge is checked.
+var pullRequestMergePollInterval = 2 * time.Second
+
+func resourcePullRequest() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourcePullRequestCreate,
+		ReadWithoutTimeout:   resourcePullRequestRead,
+		UpdateWithoutTimeout: resourcePullRequestUpdate,
+		DeleteWithoutTimeout: resourcePullRequestDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				workspace, repoSlug, _, err := pullRequestId(d.Id())
+				if err != nil {
+					return nil, err
+				}
+				d.Set("workspace", workspace)
+				d.Set("repo_slug", repoSlug)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"title": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"source_branch": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"source_repository": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Full name (workspace/repo) of the repository holding the source branch, for pull requests from a fork",
+			},
+			"destination_branch": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Branch to merge into. Defaults to the repository's main branch",
+			},
+			"reviewers": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Account UUIDs of the reviewers",
+			},
+			"close_source_branch": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"merge": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"strategy": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "merge_commit",
+							ValidateFunc: validation.StringInSlice([]string{
+								"merge_commit",
+								"squash",
+								"fast_forward",
+								"squash_fast_forward",
+								"rebase_fast_forward",
+								"rebase_merge",
+							}, false),
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"pull_request_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"source_commit": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"destination_commit": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"merge_commit": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// PullRequestInput is the body sent to create or update a pull request.
+type PullRequestInput struct {
+	Title             string                     `json:"title"`
+	Description       string                     `json:"description"`
+	Source            *PullRequestInputBranch    `json:"source,omitempty"`
+	Destination       *PullRequestInputBranch    `json:"destination,omitempty"`
+	Reviewers         []PullRequestInputReviewer `json:"reviewers"`
+	CloseSourceBranch bool                       `json:"close_source_branch"`
+}
+
+// PullRequestInputBranch is the source or destination of a PullRequestInput.
+type PullRequestInputBranch struct {
+	Branch     PullRequestBranchInfo       `json:"branch"`
+	Repository *PullRequestInputRepository `json:"repository,omitempty"`
+}
+
+// PullRequestInputRepository identifies a repository by its full name.
+type PullRequestInputRepository struct {
+	FullName string `json:"full_name"`
+}
+
+// PullRequestInputReviewer identifies a reviewer by account UUID.
+type PullRequestInputReviewer struct {
+	UUID string `json:"uuid"`
+}
+
+// ManagedPullRequest is a pull request as returned to the resource.
+type ManagedPullRequest struct {
+	ID                int                       `json:"id"`
+	Title             string                    `json:"title"`
+	Description       string                    `json:"description"`
+	State             string                    `json:"state"`
+	Source            PullRequestBranch         `json:"source"`
+	Destination       PullRequestBranch         `json:"destination"`
+	Reviewers         []bitbucket.Account       `json:"reviewers"`
+	CloseSourceBranch bool                      `json:"close_source_branch"`
+	MergeCommit       *PullRequestCommit        `json:"merge_commit,omitempty"`
+	Links             map[string]bitbucket.Link `json:"links"`
+}
+
+// PullRequestMergeParameters is the body sent to merge a pull request.
+type PullRequestMergeParameters struct {
+	Type              string `json:"type"`
+	Message           string `json:"message,omitempty"`
+	CloseSourceBranch bool   `json:"close_source_branch"`
+	MergeStrategy     string `json:"merge_strategy"`
+}
+
+func pullRequestId(id string) (string, string, string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/PULL-REQUEST-ID", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func expandPullRequestInput(d *schema.ResourceData) PullRequestInput {
+	input := PullRequestInput{
+		Title:             d.Get("title").(string),
+		Description:       d.Get("description").(string),
+		Reviewers:         []PullRequestInputReviewer{},
+		CloseSourceBranch: d.Get("close_source_branch").(bool),
+	}
+
+	for _, uuid := range d.Get("reviewers").(*schema.Set).List() {
+		input.Reviewers = append(input.Reviewers, PullRequestInputReviewer{UUID: uuid.(string)})
+	}
+
+	if v, ok := d.GetOk("destination_branch"); ok {
+		input.Destination = &PullRequestInputBranch{Branch: PullRequestBranchInfo{Name: v.(string)}}
+	}
+
+	return input
+}
+
+func resourcePullRequestCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+
+	input := expandPullRequestInput(d)
+	input.Source = &PullRequestInputBranch{Branch: PullRequestBranchInfo{Name: d.Get("source_branch").(string)}}
+	if v, ok := d.GetOk("source_repository"); ok {
+		input.Source.Repository = &PullRequestInputRepository{FullName: v.(string)}
+	}
+
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := client.PostContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/pullrequests", workspace, repoSlug), bytes.NewBuffer(payload))
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
+	pr, err := decodeManagedPullRequest(res)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%d", workspace, repoSlug, pr.ID))
+	log.Printf("[DEBUG] Created pull request %d in repository %s/%s", pr.ID, workspace, repoSlug)
+
+	if _, ok := d.GetOk("merge"); ok {
+		if err := mergePullRequest(ctx, &client, d, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourcePullRequestRead(ctx, d, m)
+}
+
+func resourcePullRequestRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, id, err := pullRequestId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s", workspace, repoSlug, id))
+	if IsNotFound(err) {
+		log.Printf("[WARN] Pull Request (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	pr, err := decodeManagedPullRequest(res)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if pr.State == "DECLINED" || pr.State == "SUPERSEDED" {
+		log.Printf("[WARN] Pull Request (%s) is %s, removing from state", d.Id(), strings.ToLower(pr.State))
+		d.SetId("")
+		return nil
+	}
+
+	reviewers := make([]string, 0, len(pr.Reviewers))
+	for _, reviewer := range pr.Reviewers {
+		reviewers = append(reviewers, reviewer.Uuid)
+	}
+
+	d.Set("workspace", workspace)
+	d.Set("repo_slug", repoSlug)
+	d.Set("pull_request_id", pr.ID)
+	d.Set("title", pr.Title)
+	d.Set("description", pr.Description)
+	d.Set("state", pr.State)
+	d.Set("source_branch", pr.Source.Branch.Name)
+	d.Set("source_repository", pr.Source.Repository.FullName)
+	d.Set("source_commit", pr.Source.Commit.Hash)
+	d.Set("destination_branch", pr.Destination.Branch.Name)
+	d.Set("destination_commit", pr.Destination.Commit.Hash)
+	d.Set("reviewers", reviewers)
+	d.Set("close_source_branch", pr.CloseSourceBranch)
+	if pr.MergeCommit != nil {
+		d.Set("merge_commit", pr.MergeCommit.Hash)
+	} else {
+		d.Set("merge_commit", "")
+	}
+	d.Set("url", pr.Links["html"].Href)
+
+	return nil
+}
+
+func resourcePullRequestUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, id, err := pullRequestId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChanges("title", "description", "destination_branch", "reviewers", "close_source_branch") {
+		if state := d.Get("state").(string); state != "" && state != "OPEN" {
+			return diag.Errorf("pull request %s is %s and can no longer be updated", d.Id(), strings.ToLower(state))
+		}
+
+		payload, err := json.Marshal(expandPullRequestInput(d))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		_, err = client.PutContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s", workspace, repoSlug, id), bytes.NewBuffer(payload))
+		if err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+	}
+
+	if _, ok := d.GetOk("merge"); ok && d.Get("state").(string) == "OPEN" {
+		if err := mergePullRequest(ctx, &client, d, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourcePullRequestRead(ctx, d, m)
+}
+
+func resourcePullRequestDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, id, err := pullRequestId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s", workspace, repoSlug, id)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	pr, err := decodeManagedPullRequest(res)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Merged and declined pull requests cannot be deleted, so they are only
+	// removed from state.
+	if pr.State != "OPEN" {
+		log.Printf("[DEBUG] Pull request %s is %s, not declining", d.Id(), pr.State)
+		return nil
+	}
+
+	_, err = client.PostContext(ctx, endpoint+"/decline", nil)
+	if err != nil && !IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func decodeManagedPullRequest(res *http.Response) (*ManagedPullRequest, error) {
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr ManagedPullRequest
+	if err := json.Unmarshal(body, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// mergePullRequest merges the pull request with the strategy of the `merge`
+// block. The merge is requested asynchronously; when Bitbucket accepts it with
+// a 202, the task status linked from the Location header is polled until the
+// merge finishes or timeout expires.
+func mergePullRequest(ctx context.Context, client *Client, d *schema.ResourceData, timeout time.Duration) error {
+	workspace, repoSlug, id, err := pullRequestId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	merge := d.Get("merge").([]interface{})[0].(map[string]interface{})
+	params := PullRequestMergeParameters{
+		Type:              "pullrequest_merge_parameters",
+		Message:           merge["message"].(string),
+		CloseSourceBranch: d.Get("close_source_branch").(bool),
+		MergeStrategy:     merge["strategy"].(string),
+	}
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	res, err := client.PostContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s/merge?async=true", workspace, repoSlug, id), bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("error merging pull request %s: %w", d.Id(), err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusAccepted {
+		log.Printf("[DEBUG] Merged pull request %s", d.Id())
+		return nil
+	}
+
+	location := res.Header.Get("Location")
+	if location == "" {
+		return fmt.Errorf("merge of pull request %s was accepted without a task status link", d.Id())
+	}
+	statusEndpoint := toRelativeEndpoint(client.baseURL(), location)
+
+	for {
+		status, err := getMergeTaskStatus(ctx, client, statusEndpoint)
+		if err != nil {
+			return fmt.Errorf("error merging pull request %s: %w", d.Id(), err)
+		}
+
+		switch status.TaskStatus {
+		case "SUCCESS":
+			log.Printf("[DEBUG] Merged pull request %s", d.Id())
+			return nil
+		case "PENDING", "":
+			log.Printf("[DEBUG] Waiting for the merge of pull request %s", d.Id())
+		default:
+			return fmt.Errorf("merge of pull request %s finished with status %s", d.Id(), status.TaskStatus)
+		}
+
+		if err := sleepContext(ctx, pullRequestMergePollInterval); err != nil {
+			return fmt.Errorf("timeout while waiting for the merge of pull request %s: %w", d.Id(), err)
+		}
+	}
+}
+
+func getMergeTaskStatus(ctx context.Context, client *Client, endpoint string) (*MergeTaskStatus, error) {
+	res, err := client.GetContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var status MergeTaskStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
@@ -0,0 +1,202 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketPullRequest_basic(t *testing.T) {
+	resourceName := "bitbucket_pull_request.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketPullRequestDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketPullRequestConfig(workspace, rName, "Add feature"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "title", "Add feature"),
+					resource.TestCheckResourceAttr(resourceName, "state", "OPEN"),
+					resource.TestCheckResourceAttr(resourceName, "destination_branch", "main"),
+					resource.TestCheckResourceAttrSet(resourceName, "pull_request_id"),
+					resource.TestCheckResourceAttrSet(resourceName, "source_commit"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"close_source_branch"},
+			},
+			{
+				Config: testAccBitbucketPullRequestConfig(workspace, rName, "Add feature, updated"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "title", "Add feature, updated"),
+					resource.TestCheckResourceAttr(resourceName, "state", "OPEN"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckBitbucketPullRequestDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(Clients).httpClient
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "bitbucket_pull_request" {
+			continue
+		}
+
+		workspace, repoSlug, id, err := pullRequestId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		response, err := client.Get(fmt.Sprintf("2.0/repositories/%s/%s/pullrequests/%s", workspace, repoSlug, id))
+		if IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		var pr struct {
+			State string `json:"state"`
+		}
+		if err := json.NewDecoder(response.Body).Decode(&pr); err != nil {
+			return err
+		}
+		if pr.State == "OPEN" {
+			return fmt.Errorf("Pull Request %s is still open", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccBitbucketPullRequestConfig(workspace, rName, title string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+}
+
+resource "bitbucket_commit_file" "main" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.name
+  branch         = "main"
+  filename       = "README.md"
+  content        = "main"
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "initial commit"
+}
+
+resource "bitbucket_commit_file" "feature" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.name
+  branch         = "feature"
+  filename       = "feature.txt"
+  content        = "feature"
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "add feature"
+
+  depends_on = [bitbucket_commit_file.main]
+}
+
+resource "bitbucket_pull_request" "test" {
+  workspace          = bitbucket_repository.test.owner
+  repo_slug          = bitbucket_repository.test.name
+  title              = %[3]q
+  description        = "Opened by the acceptance tests"
+  source_branch      = bitbucket_commit_file.feature.branch
+  destination_branch = "main"
+}
+`, workspace, rName, title)
+}
+
+func TestPullRequestID(t *testing.T) {
+	workspace, repo, id, err := pullRequestId("acme/platform/42")
+	if err != nil || workspace != "acme" || repo != "platform" || id != "42" {
+		t.Errorf("pullRequestId = %q, %q, %q, %v", workspace, repo, id, err)
+	}
+	if _, _, _, err := pullRequestId("acme/platform"); err == nil {
+		t.Error("expected error for an ID without the pull request number")
+	}
+}
+
+func TestMergePullRequestPollsTaskStatus(t *testing.T) {
+	defer func(interval time.Duration) { pullRequestMergePollInterval = interval }(pullRequestMergePollInterval)
+	pullRequestMergePollInterval = time.Millisecond
+
+	var merge PullRequestMergeParameters
+	polls := 0
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/2.0/repositories/acme/platform/pullrequests/42/merge":
+			if r.URL.Query().Get("async") != "true" {
+				t.Errorf("merge was not requested asynchronously: %q", r.URL.RawQuery)
+			}
+			json.NewDecoder(r.Body).Decode(&merge)
+			w.Header().Set("Location", "https://api.bitbucket.org/2.0/repositories/acme/platform/pullrequests/42/merge/task-status/t1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.URL.Path == "/2.0/repositories/acme/platform/pullrequests/42/merge/task-status/t1":
+			polls++
+			if polls < 3 {
+				w.Write([]byte(`{"task_status":"PENDING"}`))
+				return
+			}
+			w.Write([]byte(`{"task_status":"SUCCESS","merge_result":{"id":42,"state":"MERGED"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	d := resourcePullRequest().TestResourceData()
+	d.SetId("acme/platform/42")
+	d.Set("close_source_branch", true)
+	d.Set("merge", []interface{}{map[string]interface{}{"strategy": "squash", "message": "Release"}})
+
+	if err := mergePullRequest(context.Background(), client, d, time.Minute); err != nil {
+		t.Fatalf("mergePullRequest returned error: %v", err)
+	}
+	if polls != 3 {
+		t.Errorf("task status polled %d times, want 3", polls)
+	}
+	if merge.MergeStrategy != "squash" || merge.Message != "Release" || !merge.CloseSourceBranch {
+		t.Errorf("unexpected merge parameters: %+v", merge)
+	}
+}
+
+func TestMergePullRequestTimesOut(t *testing.T) {
+	defer func(interval time.Duration) { pullRequestMergePollInterval = interval }(pullRequestMergePollInterval)
+	pullRequestMergePollInterval = 5 * time.Millisecond
+
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Header().Set("Location", "/2.0/repositories/acme/platform/pullrequests/42/merge/task-status/t1")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Write([]byte(`{"task_status":"PENDING"}`))
+	})
+
+	d := resourcePullRequest().TestResourceData()
+	d.SetId("acme/platform/42")
+	d.Set("merge", []interface{}{map[string]interface{}{"strategy": "merge_commit", "message": ""}})
+
+	err := mergePullRequest(context.Background(), client, d, 30*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "42") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+}
@@ -7,9 +7,9 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/DrFaust92/bitbucket-go-client"
 	"github.com/antihax/optional"
@@ -18,6 +18,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// repositoryReadyPollInterval is how often a new repository is checked while
+// waiting for it to be ready.
+var repositoryReadyPollInterval = 2 * time.Second
+
 func resourceRepository() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceRepositoryCreate,
@@ -71,6 +75,12 @@ func resourceRepository() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+			"wait_for_ready": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether create waits until the repository, and its main branch if it has commits, can be read",
+			},
 			"fork_policy": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -196,9 +206,9 @@ func resourceRepositoryUpdate(ctx context.Context, d *schema.ResourceData, m int
 		repoBody := &bitbucket.RepositoriesApiRepositoriesWorkspaceRepoSlugPutOpts{
 			Body: optional.NewInterface(repository),
 		}
-		_, res, err := repoApi.RepositoriesWorkspaceRepoSlugPut(c.AuthContext, repoSlug, workspace, repoBody)
+		_, res, err := repoApi.RepositoriesWorkspaceRepoSlugPut(c.requestContext(ctx), repoSlug, workspace, repoBody)
 		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 
@@ -220,9 +230,9 @@ func resourceRepositoryUpdate(ctx context.Context, d *schema.ResourceData, m int
 		if v, ok := d.GetOkExists("pipelines_enabled"); ok {
 			pipelinesConfig := &bitbucket.PipelinesConfig{Enabled: v.(bool)}
 
-			_, res, err := pipeApi.UpdateRepositoryPipelineConfig(c.AuthContext, *pipelinesConfig, workspace, repoSlug)
+			_, res, err := pipeApi.UpdateRepositoryPipelineConfig(c.requestContext(ctx), *pipelinesConfig, workspace, repoSlug)
 			if err := handleClientError(res, err); err != nil {
-				return diag.FromErr(err)
+				return apiErrorDiagnostics(err, d)
 			}
 		}
 	}
@@ -239,13 +249,13 @@ func resourceRepositoryUpdate(ctx context.Context, d *schema.ResourceData, m int
 
 		log.Printf("Repository Inheritance Settings update encoded is: %v", string(payload))
 
-		_, err = client.Put(fmt.Sprintf("2.0/repositories/%s/%s/override-settings",
+		_, err = client.PutContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/override-settings",
 			workspace,
 			repoSlug,
 		), bytes.NewBuffer(payload))
 
 		if err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 
@@ -273,20 +283,26 @@ func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, m int
 		Body: optional.NewInterface(repo),
 	}
 
-	_, res, err := repoApi.RepositoriesWorkspaceRepoSlugPost(c.AuthContext, repoSlug, workspace, repoBody)
+	_, res, err := repoApi.RepositoriesWorkspaceRepoSlugPost(c.requestContext(ctx), repoSlug, workspace, repoBody)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s", d.Get("owner").(string), repoSlug))
 
+	if d.Get("wait_for_ready").(bool) {
+		if err := waitForRepositoryReady(ctx, &client, workspace, repoSlug, false); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	// nolint:staticcheck
 	if v, ok := d.GetOkExists("pipelines_enabled"); ok {
 		pipelinesConfig := &bitbucket.PipelinesConfig{Enabled: v.(bool)}
 
-		_, res, err := pipeApi.UpdateRepositoryPipelineConfig(c.AuthContext, *pipelinesConfig, workspace, repoSlug)
+		_, res, err := pipeApi.UpdateRepositoryPipelineConfig(c.requestContext(ctx), *pipelinesConfig, workspace, repoSlug)
 		if err := handleClientError(res, err); err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 	}
 
@@ -303,13 +319,13 @@ func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, m int
 			return diag.FromErr(err)
 		}
 
-		_, err = client.Put(fmt.Sprintf("2.0/repositories/%s/%s/override-settings",
+		_, err = client.PutContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/override-settings",
 			workspace,
 			repoSlug,
 		), bytes.NewBuffer(payload))
 
 		if err != nil {
-			return diag.FromErr(err)
+			return apiErrorDiagnostics(err, d)
 		}
 
 	}
@@ -317,6 +333,90 @@ func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, m int
 	return resourceRepositoryRead(ctx, d, m)
 }
 
+// waitForRepositoryReady polls until the repository can be read and its main
+// branch resolves. Bitbucket creates repositories, and especially forks,
+// asynchronously, so requests made right after the create can fail with a
+// 404. An empty repository has no main branch yet; unless requireBranch is
+// set it is ready as soon as it can be read.
+func waitForRepositoryReady(ctx context.Context, client *Client, workspace, repoSlug string, requireBranch bool) error {
+	for {
+		ready, err := repositoryReady(ctx, client, workspace, repoSlug, requireBranch)
+		if err != nil && ctx.Err() != nil {
+			return fmt.Errorf("timeout while waiting for repository %s/%s to be ready: %w", workspace, repoSlug, ctx.Err())
+		}
+		if err != nil {
+			return fmt.Errorf("error waiting for repository %s/%s: %w", workspace, repoSlug, err)
+		}
+		if ready {
+			log.Printf("[DEBUG] Repository %s/%s is ready", workspace, repoSlug)
+			return nil
+		}
+
+		log.Printf("[DEBUG] Waiting for repository %s/%s to be ready", workspace, repoSlug)
+		if err := sleepContext(ctx, repositoryReadyPollInterval); err != nil {
+			return fmt.Errorf("timeout while waiting for repository %s/%s to be ready: %w", workspace, repoSlug, err)
+		}
+	}
+}
+
+func repositoryReady(ctx context.Context, client *Client, workspace, repoSlug string, requireBranch bool) (bool, error) {
+	res, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s", workspace, repoSlug))
+	if IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+	var repo bitbucket.Repository
+	if err := json.Unmarshal(body, &repo); err != nil {
+		return false, err
+	}
+
+	if repo.Mainbranch != nil && repo.Mainbranch.Name != "" {
+		_, err := getBranchHead(ctx, client, workspace, repoSlug, repo.Mainbranch.Name)
+		if err == nil {
+			return true, nil
+		}
+		if !IsNotFound(err) {
+			return false, err
+		}
+	}
+	if requireBranch {
+		return false, nil
+	}
+
+	// Without a main branch the repository is only ready if it is empty.
+	empty, err := repositoryEmpty(ctx, client, workspace, repoSlug)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	return empty, err
+}
+
+// repositoryEmpty reports whether the repository has no branches, as is the
+// case until the first commit is pushed.
+func repositoryEmpty(ctx context.Context, client *Client, workspace, repoSlug string) (bool, error) {
+	res, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/refs/branches?pagelen=1", workspace, repoSlug))
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	var branches struct {
+		Values []json.RawMessage `json:"values"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&branches); err != nil {
+		return false, err
+	}
+	return len(branches.Values) == 0, nil
+}
+
 func resourceRepositoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(Clients).genClient
 	repoApi := c.ApiClient.RepositoriesApi
@@ -343,17 +443,17 @@ func resourceRepositoryRead(ctx context.Context, d *schema.ResourceData, m inter
 	}
 	repoSlug = computeSlug(repoSlug)
 
-	repoRes, res, err := repoApi.RepositoriesWorkspaceRepoSlugGet(c.AuthContext, repoSlug, workspace)
+	repoRes, res, err := repoApi.RepositoriesWorkspaceRepoSlugGet(c.requestContext(ctx), repoSlug, workspace)
 
 	// Check for nil response first to avoid nil pointer dereference
-	if res != nil && res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Repository (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.Set("owner", workspace)
@@ -371,6 +471,12 @@ func resourceRepositoryRead(ctx context.Context, d *schema.ResourceData, m inter
 		d.Set("project_key", repoRes.Project.Key)
 	}
 	d.Set("uuid", repoRes.Uuid)
+	// State written before wait_for_ready existed has no value for it, which
+	// would otherwise show up as a change to the default. Forked repositories
+	// share this Read but have no wait_for_ready.
+	if waitForReady, ok := d.Get("wait_for_ready").(bool); ok {
+		d.Set("wait_for_ready", waitForReady)
+	}
 
 	if repoRes.Links != nil && repoRes.Links.Clone != nil {
 		for _, cloneURL := range repoRes.Links.Clone {
@@ -386,21 +492,16 @@ func resourceRepositoryRead(ctx context.Context, d *schema.ResourceData, m inter
 		d.Set("link", flattenLinks(repoRes.Links))
 	}
 
-	pipelinesConfigReq, res, err := pipeApi.GetRepositoryPipelineConfig(c.AuthContext, workspace, repoSlug)
-	if err := handleClientError(res, err); err != nil && res != nil && res.StatusCode != http.StatusNotFound {
+	pipelinesConfigReq, res, err := pipeApi.GetRepositoryPipelineConfig(c.requestContext(ctx), workspace, repoSlug)
+	if IsNotFound(err) {
+		d.Set("pipelines_enabled", false)
+	} else if err := handleClientError(res, err); err != nil {
 		return diag.FromErr(err)
+	} else {
+		d.Set("pipelines_enabled", pipelinesConfigReq.Enabled)
 	}
 
-	if res != nil {
-		switch res.StatusCode {
-		case http.StatusOK:
-			d.Set("pipelines_enabled", pipelinesConfigReq.Enabled)
-		case http.StatusNotFound:
-			d.Set("pipelines_enabled", false)
-		}
-	}
-
-	settingReq, err := client.Get(fmt.Sprintf("2.0/repositories/%s/%s/override-settings",
+	settingReq, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/override-settings",
 		workspace,
 		repoSlug,
 	))
@@ -442,9 +543,9 @@ func resourceRepositoryDelete(ctx context.Context, d *schema.ResourceData, m int
 	c := m.(Clients).genClient
 	repoApi := c.ApiClient.RepositoriesApi
 
-	res, err := repoApi.RepositoriesWorkspaceRepoSlugDelete(c.AuthContext, repoSlug, d.Get("owner").(string), nil)
+	res, err := repoApi.RepositoriesWorkspaceRepoSlugDelete(c.requestContext(ctx), repoSlug, d.Get("owner").(string), nil)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return nil
@@ -0,0 +1,246 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRepositoryDownload() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceRepositoryDownloadCreate,
+		ReadWithoutTimeout:   resourceRepositoryDownloadRead,
+		DeleteWithoutTimeout: resourceRepositoryDownloadDelete,
+		CustomizeDiff:        repositoryDownloadSourceDiff,
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"source": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Local path of the file to upload",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Name of the download. Defaults to the file name of `source`",
+				ValidateFunc: validation.All(
+					validation.StringIsNotEmpty,
+					validation.StringDoesNotContainAny("/"),
+				),
+			},
+			"source_sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "SHA-256 checksum of the uploaded file",
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"downloads": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of times the file was downloaded",
+			},
+			"created_on": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// repositoryDownloadSourceDiff plans a replacement when the content of
+// `source` no longer matches the uploaded file. A file that does not exist yet,
+// for example because it is built during the apply, keeps the existing
+// download.
+func repositoryDownloadSourceDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Get("name").(string) == "" && d.NewValueKnown("source") {
+		d.SetNew("name", filepath.Base(d.Get("source").(string)))
+	}
+
+	if !d.NewValueKnown("source") {
+		return d.SetNewComputed("source_sha256")
+	}
+
+	sum, err := fileSHA256(d.Get("source").(string))
+	if os.IsNotExist(err) {
+		if d.Id() == "" {
+			return d.SetNewComputed("source_sha256")
+		}
+		log.Printf("[WARN] Download source %s not found, keeping the uploaded file", d.Get("source").(string))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if sum != d.Get("source_sha256").(string) {
+		if err := d.SetNew("source_sha256", sum); err != nil {
+			return err
+		}
+		if d.Id() != "" {
+			return d.ForceNew("source_sha256")
+		}
+	}
+	return nil
+}
+
+func repositoryDownloadId(id string) (string, string, string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/NAME", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func resourceRepositoryDownloadCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	source := d.Get("source").(string)
+	name := d.Get("name").(string)
+	if name == "" {
+		name = filepath.Base(source)
+	}
+
+	file, err := os.Open(source)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer file.Close()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("files", name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// Hash the bytes as they are uploaded, which may differ from the plan if
+	// the file was rebuilt in between.
+	hash := sha256.New()
+	if _, err := io.Copy(part, io.TeeReader(file, hash)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := writer.Close(); err != nil {
+		return diag.FromErr(err)
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	_, err = client.PostWithContentTypeContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/downloads", workspace, repoSlug), writer.FormDataContentType(), body)
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", workspace, repoSlug, name))
+	d.Set("name", name)
+	d.Set("source_sha256", sum)
+	log.Printf("[DEBUG] Uploaded download %s to repository %s/%s", name, workspace, repoSlug)
+
+	return resourceRepositoryDownloadRead(ctx, d, m)
+}
+
+func resourceRepositoryDownloadRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, name, err := repositoryDownloadId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Fetching a single download redirects to its content, so its metadata
+	// is looked up in the listing instead.
+	values, err := client.GetPaginatedContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/downloads?pagelen=100", workspace, repoSlug))
+	if IsNotFound(err) {
+		log.Printf("[WARN] Repository Download (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var download *RepositoryDownload
+	for _, raw := range values {
+		var v RepositoryDownload
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return diag.FromErr(err)
+		}
+		if v.Name == name {
+			download = &v
+			break
+		}
+	}
+	if download == nil {
+		log.Printf("[WARN] Repository Download (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("workspace", workspace)
+	d.Set("repo_slug", repoSlug)
+	d.Set("name", download.Name)
+	d.Set("size", download.Size)
+	d.Set("downloads", download.Downloads)
+	d.Set("created_on", download.CreatedOn)
+	d.Set("url", "")
+	if self, ok := download.Links["self"].(map[string]interface{}); ok {
+		d.Set("url", self["href"])
+	}
+
+	return nil
+}
+
+func resourceRepositoryDownloadDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, name, err := repositoryDownloadId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_, err = client.DeleteContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/downloads/%s", workspace, repoSlug, url.PathEscape(name)))
+	if err != nil && !IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketRepositoryDownload_basic(t *testing.T) {
+	resourceName := "bitbucket_repository_download.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+	source := filepath.Join(t.TempDir(), "release.txt")
+
+	write := func(content string) func() {
+		return func() {
+			if err := os.WriteFile(source, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	write("v1")()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDownloadDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepositoryDownloadConfig(workspace, rName, source),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "release.txt"),
+					resource.TestCheckResourceAttr(resourceName, "size", "2"),
+					resource.TestCheckResourceAttr(resourceName, "source_sha256", contentSHA256("v1")),
+				),
+			},
+			{
+				// A changed source file replaces the download.
+				PreConfig: write("v2.0"),
+				Config:    testAccBitbucketRepositoryDownloadConfig(workspace, rName, source),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "size", "4"),
+					resource.TestCheckResourceAttr(resourceName, "source_sha256", contentSHA256("v2.0")),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckBitbucketRepositoryDownloadDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(Clients).httpClient
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "bitbucket_repository_download" {
+			continue
+		}
+
+		workspace, repoSlug, name, err := repositoryDownloadId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Get(fmt.Sprintf("2.0/repositories/%s/%s/downloads/%s", workspace, repoSlug, name))
+		if err == nil {
+			return fmt.Errorf("Repository Download %s still exists", rs.Primary.ID)
+		}
+		if !IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccBitbucketRepositoryDownloadConfig(workspace, rName, source string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+}
+
+resource "bitbucket_repository_download" "test" {
+  workspace = bitbucket_repository.test.owner
+  repo_slug = bitbucket_repository.test.name
+  source    = %[3]q
+}
+`, workspace, rName, source)
+}
+
+func TestRepositoryDownloadSourceDiff(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "tool.tar.gz")
+	os.WriteFile(source, []byte("v1"), 0o644)
+	sum, _ := fileSHA256(source)
+
+	r := resourceRepositoryDownload()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"workspace": "acme",
+		"repo_slug": "platform",
+		"source":    source,
+	})
+
+	diff, err := r.Diff(context.Background(), nil, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := diff.Attributes["name"].New; got != "tool.tar.gz" {
+		t.Errorf("planned name %q, want tool.tar.gz", got)
+	}
+	if got := diff.Attributes["source_sha256"].New; got != sum {
+		t.Errorf("planned source_sha256 %q, want %q", got, sum)
+	}
+
+	state := &terraform.InstanceState{
+		ID: "acme/platform/tool.tar.gz",
+		Attributes: map[string]string{
+			"id":            "acme/platform/tool.tar.gz",
+			"workspace":     "acme",
+			"repo_slug":     "platform",
+			"source":        source,
+			"name":          "tool.tar.gz",
+			"source_sha256": sum,
+		},
+	}
+	diff, err = r.Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && len(diff.Attributes) > 0 {
+		t.Errorf("unchanged file planned a diff: %v", diff.Attributes)
+	}
+
+	os.WriteFile(source, []byte("v2"), 0o644)
+	diff, err = r.Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || !diff.RequiresNew() {
+		t.Errorf("changed file did not plan a replacement: %v", diff)
+	}
+}
+
+func TestRepositoryDownloadLifecycle(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "tool.tar.gz")
+	os.WriteFile(source, []byte("binary"), 0o644)
+
+	uploaded := ""
+	deleted := false
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/2.0/repositories/acme/platform/downloads":
+			file, header, err := r.FormFile("files")
+			if err != nil {
+				t.Fatalf("no file uploaded: %v", err)
+			}
+			content, _ := io.ReadAll(file)
+			uploaded = header.Filename + ":" + string(content)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && r.URL.Path == "/2.0/repositories/acme/platform/downloads":
+			if r.URL.Query().Get("page") == "" {
+				w.Write([]byte(`{"next":"/2.0/repositories/acme/platform/downloads?pagelen=100&page=2","values":[{"name":"other.zip","size":1}]}`))
+				return
+			}
+			w.Write([]byte(`{"values":[{"name":"cli-1.0.tar.gz","size":6,"downloads":3,"created_on":"2026-10-17T00:00:00Z",
+				"links":{"self":{"href":"https://api.bitbucket.org/2.0/repositories/acme/platform/downloads/cli-1.0.tar.gz"}}}]}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/2.0/repositories/acme/platform/downloads/cli-1.0.tar.gz":
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceRepositoryDownload().Schema, map[string]interface{}{
+		"workspace": "acme",
+		"repo_slug": "platform",
+		"source":    source,
+		"name":      "cli-1.0.tar.gz",
+	})
+
+	if diags := resourceRepositoryDownloadCreate(context.Background(), d, Clients{httpClient: *client}); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if uploaded != "cli-1.0.tar.gz:binary" {
+		t.Errorf("uploaded %q", uploaded)
+	}
+	if d.Id() != "acme/platform/cli-1.0.tar.gz" || d.Get("size") != 6 || d.Get("downloads") != 3 || d.Get("source_sha256") != contentSHA256("binary") {
+		t.Errorf("unexpected state: id=%s size=%v downloads=%v sha=%v", d.Id(), d.Get("size"), d.Get("downloads"), d.Get("source_sha256"))
+	}
+	if d.Get("url") != "https://api.bitbucket.org/2.0/repositories/acme/platform/downloads/cli-1.0.tar.gz" {
+		t.Errorf("url = %v", d.Get("url"))
+	}
+
+	if diags := resourceRepositoryDownloadDelete(context.Background(), d, Clients{httpClient: *client}); diags.HasError() || !deleted {
+		t.Errorf("download not deleted: %v", diags)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -84,14 +83,14 @@ func resourceRepositoryGroupPermissionPut(ctx context.Context, d *schema.Resourc
 	repoSlug := d.Get("repo_slug").(string)
 	groupSlug := d.Get("group_slug").(string)
 
-	permissionReq, err := client.Put(fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/groups/%s",
+	permissionReq, err := client.PutContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/groups/%s",
 		workspace,
 		repoSlug,
 		groupSlug,
 	), bytes.NewBuffer(payload))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(permissionReq.Body)
@@ -119,13 +118,13 @@ func resourceRepositoryGroupPermissionRead(ctx context.Context, d *schema.Resour
 		return diag.FromErr(err)
 	}
 
-	permissionReq, err := client.Get(fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/groups/%s",
+	permissionReq, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/groups/%s",
 		workspace,
 		repoSlug,
 		groupSlug,
 	))
 
-	if permissionReq.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Repository Group Permission (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -167,7 +166,7 @@ func resourceRepositoryGroupPermissionDelete(ctx context.Context, d *schema.Reso
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Delete(fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/groups/%s",
+	_, err = client.DeleteContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/groups/%s",
 		workspace,
 		repoSlug,
 		groupSlug,
@@ -0,0 +1,275 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// issueJobPollInterval is how often the status of an issue export or import
+// is checked.
+var issueJobPollInterval = 5 * time.Second
+
+func resourceRepositoryIssueExport() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceRepositoryIssueExportCreate,
+		ReadWithoutTimeout:   resourceRepositoryIssueExportRead,
+		DeleteWithoutTimeout: resourceRepositoryIssueExportDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"output_path": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Local path the exported zip archive is written to",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"include_attachments": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  true,
+			},
+			"send_email": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether Bitbucket emails a download link when the export completes",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that, when changed, run a new export",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"archive_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"output_sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"output_size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// IssueExportRequest is the body sent to start an issue export.
+type IssueExportRequest struct {
+	Type               string `json:"type"`
+	SendEmail          bool   `json:"send_email"`
+	IncludeAttachments bool   `json:"include_attachments"`
+}
+
+// IssueJobStatus is the progress of an issue export or import.
+type IssueJobStatus struct {
+	Type   string  `json:"type"`
+	Status string  `json:"status"`
+	Phase  string  `json:"phase"`
+	Total  int     `json:"total"`
+	Count  int     `json:"count"`
+	Pct    float64 `json:"pct"`
+}
+
+// running reports whether the job has yet to finish.
+func (s *IssueJobStatus) running() bool {
+	switch s.Status {
+	case "ACCEPTED", "STARTED", "RUNNING", "":
+		return true
+	}
+	return false
+}
+
+func decodeIssueJobStatus(res *http.Response) (*IssueJobStatus, error) {
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var status IssueJobStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func resourceRepositoryIssueExportCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+
+	payload, err := json.Marshal(IssueExportRequest{
+		Type:               "export_options",
+		SendEmail:          d.Get("send_email").(bool),
+		IncludeAttachments: d.Get("include_attachments").(bool),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := client.PostContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/issues/export", workspace, repoSlug), bytes.NewBuffer(payload))
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+	res.Body.Close()
+
+	location := res.Header.Get("Location")
+	if location == "" {
+		return diag.Errorf("issue export of repository %s/%s was accepted without a download link", workspace, repoSlug)
+	}
+	archiveName := path.Base(location)
+	log.Printf("[DEBUG] Started issue export %s of repository %s/%s", archiveName, workspace, repoSlug)
+
+	sum, size, err := downloadIssueExport(ctx, &client, toRelativeEndpoint(client.baseURL(), location), d.Get("output_path").(string))
+	if err != nil {
+		return diag.Errorf("error exporting issues of repository %s/%s: %s", workspace, repoSlug, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", workspace, repoSlug, archiveName))
+	d.Set("archive_name", archiveName)
+	d.Set("output_sha256", sum)
+	d.Set("output_size", size)
+
+	return nil
+}
+
+// downloadIssueExport polls the export endpoint until the archive is ready,
+// then writes it to outputPath. While the export runs Bitbucket answers with a
+// 202 and the job status; once it completes it redirects to the archive.
+func downloadIssueExport(ctx context.Context, client *Client, endpoint, outputPath string) (string, int64, error) {
+	for {
+		res, err := client.GetContext(ctx, endpoint)
+		if err != nil {
+			return "", 0, err
+		}
+
+		if res.StatusCode != http.StatusAccepted {
+			defer res.Body.Close()
+			return writeIssueArchive(res.Body, outputPath)
+		}
+
+		status, err := decodeIssueJobStatus(res)
+		if err != nil {
+			return "", 0, err
+		}
+		if !status.running() {
+			return "", 0, fmt.Errorf("export finished with status %s", status.Status)
+		}
+		log.Printf("[DEBUG] Waiting for issue export: %s %s (%d/%d)", status.Status, status.Phase, status.Count, status.Total)
+
+		if err := sleepContext(ctx, issueJobPollInterval); err != nil {
+			return "", 0, fmt.Errorf("timeout while waiting for the export: %w", err)
+		}
+	}
+}
+
+// writeIssueArchive writes the archive next to outputPath first and renames it
+// into place, so an interrupted download never leaves a truncated archive.
+func writeIssueArchive(r io.Reader, outputPath string) (string, int64, error) {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return "", 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(outputPath), filepath.Base(outputPath)+".*.tmp")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if err != nil {
+		tmp.Close()
+		return "", 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmp.Name(), outputPath); err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+func fileSHA256(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func resourceRepositoryIssueExportRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// The export itself cannot be read back; the resource only goes away
+	// when the archive it wrote is removed or changed, so the next apply
+	// exports again.
+	sum, err := fileSHA256(d.Get("output_path").(string))
+	if os.IsNotExist(err) {
+		log.Printf("[WARN] Issue export archive (%s) not found, removing from state", d.Get("output_path").(string))
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if sum != d.Get("output_sha256").(string) {
+		log.Printf("[WARN] Issue export archive (%s) was modified, removing from state", d.Get("output_path").(string))
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func resourceRepositoryIssueExportDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// The downloaded archive is left in place.
+	return nil
+}
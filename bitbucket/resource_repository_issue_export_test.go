@@ -0,0 +1,145 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketRepositoryIssueExport_basic(t *testing.T) {
+	resourceName := "bitbucket_repository_issue_export.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+	outputPath := filepath.Join(t.TempDir(), "issues.zip")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepositoryIssueExportConfig(workspace, rName, outputPath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "output_path", outputPath),
+					resource.TestCheckResourceAttrSet(resourceName, "archive_name"),
+					resource.TestCheckResourceAttrSet(resourceName, "output_sha256"),
+					testAccCheckBitbucketRepositoryIssueExportWritten(resourceName),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckBitbucketRepositoryIssueExportWritten(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found %s", n)
+		}
+
+		sum, err := fileSHA256(rs.Primary.Attributes["output_path"])
+		if err != nil {
+			return err
+		}
+		if sum != rs.Primary.Attributes["output_sha256"] {
+			return fmt.Errorf("checksum of %s is %s, state has %s", rs.Primary.Attributes["output_path"], sum, rs.Primary.Attributes["output_sha256"])
+		}
+		return nil
+	}
+}
+
+func testAccBitbucketRepositoryIssueExportConfig(workspace, rName, outputPath string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  has_issues = true
+}
+
+resource "bitbucket_issue" "test" {
+  workspace = bitbucket_repository.test.owner
+  repo_slug = bitbucket_repository.test.name
+  title     = "Exported issue"
+}
+
+resource "bitbucket_repository_issue_export" "test" {
+  workspace   = bitbucket_repository.test.owner
+  repo_slug   = bitbucket_repository.test.name
+  output_path = %[3]q
+
+  triggers = {
+    issue = bitbucket_issue.test.id
+  }
+}
+`, workspace, rName, outputPath)
+}
+
+func TestRepositoryIssueExportDownloadsArchive(t *testing.T) {
+	defer func(interval time.Duration) { issueJobPollInterval = interval }(issueJobPollInterval)
+	issueJobPollInterval = time.Millisecond
+
+	var options IssueExportRequest
+	polls := 0
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/2.0/repositories/acme/platform/issues/export":
+			json.NewDecoder(r.Body).Decode(&options)
+			w.Header().Set("Location", "https://api.bitbucket.org/2.0/repositories/acme/platform/issues/export/platform-issues-t1.zip")
+			w.WriteHeader(http.StatusAccepted)
+		case r.URL.Path == "/2.0/repositories/acme/platform/issues/export/platform-issues-t1.zip":
+			polls++
+			if polls < 3 {
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte(`{"type":"issue_job_status","status":"RUNNING","phase":"Attachments","total":10,"count":4}`))
+				return
+			}
+			http.Redirect(w, r, "/downloads/platform-issues-t1.zip", http.StatusFound)
+		case r.URL.Path == "/downloads/platform-issues-t1.zip":
+			w.Write([]byte("zip-content"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	output := filepath.Join(t.TempDir(), "exports", "platform.zip")
+	d := schema.TestResourceDataRaw(t, resourceRepositoryIssueExport().Schema, map[string]interface{}{
+		"workspace":   "acme",
+		"repo_slug":   "platform",
+		"output_path": output,
+	})
+
+	if diags := resourceRepositoryIssueExportCreate(context.Background(), d, Clients{httpClient: *client}); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !options.IncludeAttachments || options.SendEmail {
+		t.Errorf("unexpected export options: %+v", options)
+	}
+	if polls != 3 {
+		t.Errorf("polled %d times, want 3", polls)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil || string(content) != "zip-content" {
+		t.Fatalf("archive = %q, %v", content, err)
+	}
+	if d.Get("archive_name") != "platform-issues-t1.zip" || d.Get("output_size") != len("zip-content") {
+		t.Errorf("unexpected attributes: archive_name=%v output_size=%v", d.Get("archive_name"), d.Get("output_size"))
+	}
+
+	// Changing the archive outside Terraform triggers a new export.
+	if diags := resourceRepositoryIssueExportRead(context.Background(), d, nil); diags.HasError() || d.Id() == "" {
+		t.Fatalf("unchanged archive removed from state: %v", diags)
+	}
+	os.WriteFile(output, []byte("edited"), 0o644)
+	if diags := resourceRepositoryIssueExportRead(context.Background(), d, nil); diags.HasError() || d.Id() != "" {
+		t.Errorf("modified archive kept in state: %v", diags)
+	}
+}
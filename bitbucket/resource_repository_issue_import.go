@@ -0,0 +1,167 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRepositoryIssueImport() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceRepositoryIssueImportCreate,
+		ReadWithoutTimeout:   resourceRepositoryIssueImportRead,
+		DeleteWithoutTimeout: resourceRepositoryIssueImportDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"archive_path": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Local path of the zip archive to import, as produced by an issue export",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that, when changed, run a new import",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"archive_sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"imported_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of issues imported",
+			},
+		},
+	}
+}
+
+func resourceRepositoryIssueImportCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	archivePath := d.Get("archive_path").(string)
+	endpoint := fmt.Sprintf("2.0/repositories/%s/%s/issues/import", workspace, repoSlug)
+
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer archive.Close()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("archive", filepath.Base(archivePath))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if _, err := io.Copy(part, archive); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := writer.Close(); err != nil {
+		return diag.FromErr(err)
+	}
+
+	sum, err := fileSHA256(archivePath)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := client.PostWithContentTypeContext(ctx, endpoint, writer.FormDataContentType(), body)
+	if IsConflict(err) {
+		return diag.Errorf("an issue import into repository %s/%s is already in progress", workspace, repoSlug)
+	}
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+	res.Body.Close()
+
+	log.Printf("[DEBUG] Started issue import of %s into repository %s/%s", archivePath, workspace, repoSlug)
+
+	status, err := waitForIssueImport(ctx, &client, endpoint)
+	if err != nil {
+		return diag.Errorf("error importing issues into repository %s/%s: %s", workspace, repoSlug, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", workspace, repoSlug))
+	d.Set("archive_sha256", sum)
+	d.Set("status", status.Status)
+	d.Set("imported_count", status.Count)
+
+	return nil
+}
+
+// waitForIssueImport polls the import status until the job finishes.
+func waitForIssueImport(ctx context.Context, client *Client, endpoint string) (*IssueJobStatus, error) {
+	for {
+		res, err := client.GetContext(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		status, err := decodeIssueJobStatus(res)
+		if err != nil {
+			return nil, err
+		}
+		if status.Status == "FAILURE" {
+			return nil, fmt.Errorf("import finished with status %s in phase %s", status.Status, status.Phase)
+		}
+		if !status.running() {
+			return status, nil
+		}
+		log.Printf("[DEBUG] Waiting for issue import: %s %s (%d/%d)", status.Status, status.Phase, status.Count, status.Total)
+
+		if err := sleepContext(ctx, issueJobPollInterval); err != nil {
+			return nil, fmt.Errorf("timeout while waiting for the import: %w", err)
+		}
+	}
+}
+
+func resourceRepositoryIssueImportRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// An import is a one-time action; the imported issues are managed in the
+	// issue tracker, not by this resource.
+	return nil
+}
+
+func resourceRepositoryIssueImportDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Imported issues are left in place.
+	return nil
+}
@@ -0,0 +1,149 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccBitbucketRepositoryIssueImport_basic(t *testing.T) {
+	resourceName := "bitbucket_repository_issue_import.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+	archivePath := filepath.Join(t.TempDir(), "issues.zip")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepositoryIssueImportConfig(workspace, rName, archivePath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "archive_sha256", "bitbucket_repository_issue_export.test", "output_sha256"),
+					resource.TestCheckResourceAttr(resourceName, "imported_count", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketRepositoryIssueImportConfig(workspace, rName, archivePath string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "source" {
+  owner      = %[1]q
+  name       = "%[2]s-source"
+  has_issues = true
+}
+
+resource "bitbucket_issue" "test" {
+  workspace = bitbucket_repository.source.owner
+  repo_slug = bitbucket_repository.source.name
+  title     = "Imported issue"
+}
+
+resource "bitbucket_repository_issue_export" "test" {
+  workspace   = bitbucket_repository.source.owner
+  repo_slug   = bitbucket_repository.source.name
+  output_path = %[3]q
+
+  triggers = {
+    issue = bitbucket_issue.test.id
+  }
+}
+
+resource "bitbucket_repository" "target" {
+  owner      = %[1]q
+  name       = "%[2]s-target"
+  has_issues = true
+}
+
+resource "bitbucket_repository_issue_import" "test" {
+  workspace    = bitbucket_repository.target.owner
+  repo_slug    = bitbucket_repository.target.name
+  archive_path = bitbucket_repository_issue_export.test.output_path
+}
+`, workspace, rName, archivePath)
+}
+
+func TestRepositoryIssueImportUploadsArchive(t *testing.T) {
+	defer func(interval time.Duration) { issueJobPollInterval = interval }(issueJobPollInterval)
+	issueJobPollInterval = time.Millisecond
+
+	archive := filepath.Join(t.TempDir(), "platform.zip")
+	os.WriteFile(archive, []byte("zip-content"), 0o644)
+
+	polls := 0
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2.0/repositories/acme/platform/issues/import" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			file, header, err := r.FormFile("archive")
+			if err != nil {
+				t.Fatalf("no archive uploaded: %v", err)
+			}
+			content, _ := io.ReadAll(file)
+			if header.Filename != "platform.zip" || string(content) != "zip-content" {
+				t.Errorf("uploaded %s with %q", header.Filename, content)
+			}
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"type":"issue_job_status","status":"ACCEPTED"}`))
+		case http.MethodGet:
+			polls++
+			if polls < 2 {
+				w.Write([]byte(`{"type":"issue_job_status","status":"RUNNING","total":12,"count":5}`))
+				return
+			}
+			w.Write([]byte(`{"type":"issue_job_status","status":"SUCCESS","total":12,"count":12}`))
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceRepositoryIssueImport().Schema, map[string]interface{}{
+		"workspace":    "acme",
+		"repo_slug":    "platform",
+		"archive_path": archive,
+	})
+
+	if diags := resourceRepositoryIssueImportCreate(context.Background(), d, Clients{httpClient: *client}); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if d.Get("status") != "SUCCESS" || d.Get("imported_count") != 12 || d.Get("archive_sha256") == "" {
+		t.Errorf("unexpected attributes: status=%v imported_count=%v sha=%v", d.Get("status"), d.Get("imported_count"), d.Get("archive_sha256"))
+	}
+}
+
+func TestRepositoryIssueImportFailure(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "platform.zip")
+	os.WriteFile(archive, []byte("zip-content"), 0o644)
+
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"type":"issue_job_status","status":"FAILURE","phase":"Validating"}`))
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceRepositoryIssueImport().Schema, map[string]interface{}{
+		"workspace":    "acme",
+		"repo_slug":    "platform",
+		"archive_path": archive,
+	})
+
+	diags := resourceRepositoryIssueImportCreate(context.Background(), d, Clients{httpClient: *client})
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "FAILURE") {
+		t.Errorf("expected a failure, got %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("failed import stored in state as %s", d.Id())
+	}
+}
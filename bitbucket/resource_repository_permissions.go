@@ -0,0 +1,329 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRepositoryPermissions() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceRepositoryPermissionsCreate,
+		ReadWithoutTimeout:   resourceRepositoryPermissionsRead,
+		UpdateWithoutTimeout: resourceRepositoryPermissionsUpdate,
+		DeleteWithoutTimeout: resourceRepositoryPermissionsDelete,
+		CustomizeDiff:        validateRepositoryPermissions,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				workspace, repoSlug, err := repositoryId(d.Id())
+				if err != nil {
+					return nil, err
+				}
+				// Nothing is managed yet; the declared grants are adopted on
+				// the next apply.
+				d.Set("workspace", workspace)
+				d.Set("repo_slug", repoSlug)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"user": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "UUID of the user",
+							ValidateFunc: validation.StringMatch(regexp.MustCompile(`^\{[0-9a-f-]+\}$`), "must be a lowercase user UUID, such as {c788b3a5-d3f5-4b07-b1cf-b5c7a5a5a5a5}"),
+						},
+						"permission": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"admin", "write", "read"}, false),
+						},
+					},
+				},
+			},
+			"group": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group_slug": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "Slug of the group",
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"permission": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"admin", "write", "read"}, false),
+						},
+					},
+				},
+			},
+			"exclusive": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether user and group grants that are not declared are revoked",
+			},
+		},
+	}
+}
+
+// repositoryGrants maps the users or groups of a repository to their
+// permission.
+type repositoryGrants map[string]string
+
+// repositoryPermissionsKind describes one of the two kinds of grants, users
+// and groups, which share the same endpoints apart from the path segment.
+type repositoryPermissionsKind struct {
+	path  string
+	block string
+	attr  string
+}
+
+var repositoryPermissionsKinds = []repositoryPermissionsKind{
+	{path: "users", block: "user", attr: "user_id"},
+	{path: "groups", block: "group", attr: "group_slug"},
+}
+
+func (k repositoryPermissionsKind) endpoint(workspace, repoSlug string) string {
+	return fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/%s", workspace, repoSlug, k.path)
+}
+
+func getRepositoryGrants(ctx context.Context, client *Client, kind repositoryPermissionsKind, workspace, repoSlug string) (repositoryGrants, error) {
+	values, err := client.GetPaginatedContext(ctx, kind.endpoint(workspace, repoSlug)+"?pagelen=100")
+	if err != nil {
+		return nil, err
+	}
+
+	grants := make(repositoryGrants, len(values))
+	for _, raw := range values {
+		var grant struct {
+			Permission string           `json:"permission"`
+			User       *RepositoryUser  `json:"user"`
+			Group      *RepositoryGroup `json:"group"`
+		}
+		if err := json.Unmarshal(raw, &grant); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case grant.User != nil:
+			grants[grant.User.UUID] = grant.Permission
+		case grant.Group != nil:
+			grants[grant.Group.Slug] = grant.Permission
+		}
+	}
+	return grants, nil
+}
+
+// expandRepositoryGrants indexes the elements of the `user` or `group` set.
+func expandRepositoryGrants(kind repositoryPermissionsKind, set interface{}) repositoryGrants {
+	grants := repositoryGrants{}
+	for _, raw := range set.(*schema.Set).List() {
+		v := raw.(map[string]interface{})
+		grants[v[kind.attr].(string)] = v["permission"].(string)
+	}
+	return grants
+}
+
+// validateRepositoryPermissions checks at plan time that no user or group is
+// given more than one permission.
+func validateRepositoryPermissions(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	for _, kind := range repositoryPermissionsKinds {
+		if !d.NewValueKnown(kind.block) {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, raw := range d.Get(kind.block).(*schema.Set).List() {
+			id := raw.(map[string]interface{})[kind.attr].(string)
+			if seen[id] {
+				return fmt.Errorf("%s %s is declared more than once", kind.block, id)
+			}
+			seen[id] = true
+		}
+	}
+	return nil
+}
+
+func (g repositoryGrants) keys() []string {
+	keys := make([]string, 0, len(g))
+	for key := range g {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func resourceRepositoryPermissionsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+
+	d.SetId(fmt.Sprintf("%s/%s", workspace, repoSlug))
+
+	if diags := syncRepositoryPermissions(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	return resourceRepositoryPermissionsRead(ctx, d, m)
+}
+
+func resourceRepositoryPermissionsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := syncRepositoryPermissions(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	return resourceRepositoryPermissionsRead(ctx, d, m)
+}
+
+// syncRepositoryPermissions compares the declared grants with the live
+// permissions-config and applies only the differences. Grants that are no
+// longer declared are revoked; with `exclusive`, every other grant of the
+// repository is revoked as well.
+func syncRepositoryPermissions(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, err := repositoryId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	exclusive := d.Get("exclusive").(bool)
+
+	for _, kind := range repositoryPermissionsKinds {
+		o, n := d.GetChange(kind.block)
+		old := expandRepositoryGrants(kind, o)
+		desired := expandRepositoryGrants(kind, n)
+
+		live, err := getRepositoryGrants(ctx, &client, kind, workspace, repoSlug)
+		if err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+		endpoint := kind.endpoint(workspace, repoSlug)
+
+		// Grants are given before others are revoked, so that replacing a
+		// user or group never leaves the repository without access.
+		for _, id := range desired.keys() {
+			permission := desired[id]
+			if live[id] == permission {
+				continue
+			}
+
+			payload, err := json.Marshal(map[string]string{"permission": permission})
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			log.Printf("[DEBUG] Granting %s permission on %s/%s to %s %s", permission, workspace, repoSlug, kind.block, id)
+			if _, err := client.PutContext(ctx, fmt.Sprintf("%s/%s", endpoint, id), bytes.NewBuffer(payload)); err != nil {
+				return diag.Errorf("error granting %s permission to %s %s: %s", permission, kind.block, id, err)
+			}
+		}
+
+		for _, id := range live.keys() {
+			if _, ok := desired[id]; ok {
+				continue
+			}
+			if _, managed := old[id]; !managed && !exclusive {
+				continue
+			}
+
+			log.Printf("[DEBUG] Revoking permission on %s/%s from %s %s", workspace, repoSlug, kind.block, id)
+			if _, err := client.DeleteContext(ctx, fmt.Sprintf("%s/%s", endpoint, id)); err != nil && !IsNotFound(err) {
+				return diag.Errorf("error revoking permission from %s %s: %s", kind.block, id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func resourceRepositoryPermissionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, err := repositoryId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	exclusive := d.Get("exclusive").(bool)
+
+	for _, kind := range repositoryPermissionsKinds {
+		live, err := getRepositoryGrants(ctx, &client, kind, workspace, repoSlug)
+		if IsNotFound(err) {
+			log.Printf("[WARN] Repository Permissions (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		managed := expandRepositoryGrants(kind, d.Get(kind.block))
+
+		grants := make([]interface{}, 0, len(live))
+		for _, id := range live.keys() {
+			if _, ok := managed[id]; !ok && !exclusive {
+				continue
+			}
+			grants = append(grants, map[string]interface{}{
+				kind.attr:    id,
+				"permission": live[id],
+			})
+		}
+		d.Set(kind.block, grants)
+	}
+
+	d.Set("workspace", workspace)
+	d.Set("repo_slug", repoSlug)
+
+	return nil
+}
+
+func resourceRepositoryPermissionsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, err := repositoryId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, kind := range repositoryPermissionsKinds {
+		grants := expandRepositoryGrants(kind, d.Get(kind.block))
+		for _, id := range grants.keys() {
+			_, err := client.DeleteContext(ctx, fmt.Sprintf("%s/%s", kind.endpoint(workspace, repoSlug), id))
+			if err != nil && !IsNotFound(err) {
+				return diag.Errorf("error revoking permission from %s %s: %s", kind.block, id, err)
+			}
+		}
+	}
+
+	return nil
+}
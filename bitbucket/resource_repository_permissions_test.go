@@ -0,0 +1,222 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketRepositoryPermissions_basic(t *testing.T) {
+	resourceName := "bitbucket_repository_permissions.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepositoryPermissionsConfig(workspace, rName, "read"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "workspace", workspace),
+					resource.TestCheckResourceAttrPair(resourceName, "repo_slug", "bitbucket_repository.test", "name"),
+					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair(resourceName, "group.*.group_slug", "bitbucket_group.test", "slug"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.*", map[string]string{"permission": "read"}),
+				),
+			},
+			{
+				Config: testAccBitbucketRepositoryPermissionsConfig(workspace, rName, "write"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "group.*", map[string]string{"permission": "write"}),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketRepositoryPermissionsConfig(workspace, rName, permission string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+}
+
+resource "bitbucket_group" "test" {
+  workspace  = %[1]q
+  name       = %[2]q
+}
+
+resource "bitbucket_repository_permissions" "test" {
+  workspace = %[1]q
+  repo_slug = bitbucket_repository.test.name
+
+  group {
+    group_slug = bitbucket_group.test.slug
+    permission = %[3]q
+  }
+}
+`, workspace, rName, permission)
+}
+
+func TestRepositoryPermissionsLifecycle(t *testing.T) {
+	grants := map[string]map[string]string{
+		"users":  {"{u-owner}": "admin", "{u-dev}": "read"},
+		"groups": {"developers": "write"},
+	}
+	var writes []string
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "/2.0/repositories/acme/platform/permissions-config/"
+		kind, id := path.Split(strings.TrimPrefix(r.URL.Path, prefix))
+		kind = strings.TrimSuffix(kind, "/")
+		if id == "users" || id == "groups" {
+			kind, id = id, ""
+		}
+		if _, ok := grants[kind]; !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			values := []map[string]interface{}{}
+			for id, permission := range grants[kind] {
+				if kind == "users" {
+					values = append(values, map[string]interface{}{"permission": permission, "user": map[string]string{"uuid": id}})
+				} else {
+					values = append(values, map[string]interface{}{"permission": permission, "group": map[string]string{"slug": id}})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"values": values})
+		case http.MethodPut:
+			var body struct{ Permission string }
+			json.NewDecoder(r.Body).Decode(&body)
+			grants[kind][id] = body.Permission
+			writes = append(writes, fmt.Sprintf("PUT %s=%s", id, body.Permission))
+			json.NewEncoder(w).Encode(body)
+		case http.MethodDelete:
+			delete(grants[kind], id)
+			writes = append(writes, "DELETE "+id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	meta := Clients{httpClient: *client}
+	r := resourceRepositoryPermissions()
+	ctx := context.Background()
+
+	apply := func(state *terraform.InstanceState, raw map[string]interface{}) *terraform.InstanceState {
+		t.Helper()
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff == nil || diff.Empty() {
+			return state
+		}
+		state, diags := r.Apply(ctx, state, diff, meta)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		return state
+	}
+	refresh := func(state *terraform.InstanceState) *terraform.InstanceState {
+		t.Helper()
+		state, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		return state
+	}
+	raw := map[string]interface{}{
+		"workspace": "acme",
+		"repo_slug": "platform",
+		"user":      []interface{}{map[string]interface{}{"user_id": "{u-dev}", "permission": "write"}},
+		"group":     []interface{}{map[string]interface{}{"group_slug": "developers", "permission": "write"}},
+	}
+
+	// Only the grants that differ from the live configuration are written,
+	// and undeclared grants are kept.
+	state := apply(nil, raw)
+	if !reflect.DeepEqual(writes, []string{"PUT {u-dev}=write"}) || grants["users"]["{u-owner}"] != "admin" {
+		t.Fatalf("writes %v, grants %v", writes, grants)
+	}
+	if state.ID != "acme/platform" || state.Attributes["user.#"] != "1" || state.Attributes["group.#"] != "1" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+
+	// Grants changed outside Terraform are detected and reverted.
+	writes = nil
+	grants["groups"]["developers"] = "admin"
+	delete(grants["users"], "{u-dev}")
+	state = apply(refresh(state), raw)
+	if !reflect.DeepEqual(writes, []string{"PUT {u-dev}=write", "PUT developers=write"}) {
+		t.Fatalf("writes %v", writes)
+	}
+
+	// In exclusive mode undeclared grants are revoked, and grants removed
+	// from the configuration are revoked as well.
+	writes = nil
+	raw["exclusive"] = true
+	raw["group"] = []interface{}{}
+	state = apply(refresh(state), raw)
+	if !reflect.DeepEqual(writes, []string{"DELETE {u-owner}", "DELETE developers"}) {
+		t.Fatalf("writes %v", writes)
+	}
+	if state.Attributes["user.#"] != "1" || state.Attributes["group.#"] != "0" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+
+	// Grants added outside Terraform show up as drift.
+	grants["users"]["{u-intruder}"] = "admin"
+	state = refresh(state)
+	if state.Attributes["user.#"] != "2" {
+		t.Errorf("drift not detected: %v", state.Attributes)
+	}
+
+	// Destroying revokes every grant in state.
+	writes = nil
+	if _, diags := r.Apply(ctx, state, &terraform.InstanceDiff{Destroy: true}, meta); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if len(grants["users"]) != 0 || len(writes) != 2 {
+		t.Errorf("writes %v, grants %v", writes, grants)
+	}
+}
+
+func TestRepositoryPermissionsRejectsDuplicates(t *testing.T) {
+	r := resourceRepositoryPermissions()
+	ctx := context.Background()
+
+	raw := map[string]interface{}{
+		"workspace": "acme",
+		"repo_slug": "platform",
+		"user": []interface{}{
+			map[string]interface{}{"user_id": "{c788b3a5-d3f5-4b07-b1cf-b5c7a5a5a5a5}", "permission": "read"},
+			map[string]interface{}{"user_id": "{c788b3a5-d3f5-4b07-b1cf-b5c7a5a5a5a5}", "permission": "admin"},
+		},
+	}
+	if _, err := r.Diff(ctx, nil, terraform.NewResourceConfigRaw(raw), nil); err == nil || !strings.Contains(err.Error(), "declared more than once") {
+		t.Errorf("expected a plan error, got %v", err)
+	}
+
+	// User UUIDs are compared with the ones Bitbucket returns, which are
+	// lowercase.
+	raw["user"] = []interface{}{
+		map[string]interface{}{"user_id": "{C788B3A5-D3F5-4B07-B1CF-B5C7A5A5A5A5}", "permission": "read"},
+	}
+	if diags := r.Validate(terraform.NewResourceConfigRaw(raw)); !diags.HasError() {
+		t.Error("expected an uppercase user UUID to be rejected")
+	}
+}
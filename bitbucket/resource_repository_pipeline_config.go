@@ -0,0 +1,253 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRepositoryPipelineConfig() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceRepositoryPipelineConfigCreate,
+		ReadWithoutTimeout:   resourceRepositoryPipelineConfigRead,
+		UpdateWithoutTimeout: resourceRepositoryPipelineConfigUpdate,
+		DeleteWithoutTimeout: resourceRepositoryPipelineConfigDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				workspace, repoSlug, err := repositoryId(d.Id())
+				if err != nil {
+					return nil, err
+				}
+				d.Set("workspace", workspace)
+				d.Set("repo_slug", repoSlug)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"repo_slug": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Repository slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether Pipelines is enabled for the repository",
+			},
+			"next_build_number": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Lowest build number the next pipeline gets",
+				ValidateFunc: validation.IntAtLeast(1),
+				// Every pipeline run increments the build number, so only a
+				// configured number above the current one is a change.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					current, err := strconv.Atoi(old)
+					if err != nil {
+						return false
+					}
+					configured, err := strconv.Atoi(new)
+					if err != nil {
+						return false
+					}
+					return current >= configured
+				},
+			},
+		},
+	}
+}
+
+func repositoryPipelineConfigEndpoint(workspace, repoSlug string) string {
+	return fmt.Sprintf("2.0/repositories/%s/%s/pipelines_config", workspace, repoSlug)
+}
+
+// getRepositoryPipelineConfig returns the configuration as a raw object. The
+// generated PipelinesConfig model only knows `enabled` and drops every other
+// property, which would then be lost when the configuration is written back.
+func getRepositoryPipelineConfig(ctx context.Context, client *Client, workspace, repoSlug string) (map[string]interface{}, error) {
+	res, err := client.GetContext(ctx, repositoryPipelineConfigEndpoint(workspace, repoSlug))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	config := map[string]interface{}{}
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func getRepositoryBuildNumber(ctx context.Context, client *Client, workspace, repoSlug string) (int, error) {
+	res, err := client.GetContext(ctx, repositoryPipelineConfigEndpoint(workspace, repoSlug)+"/build_number")
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	var buildNumber struct {
+		Next int `json:"next"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&buildNumber); err != nil {
+		return 0, err
+	}
+	return buildNumber.Next, nil
+}
+
+// raiseRepositoryBuildNumber sets the number of the next pipeline. Build
+// numbers cannot go back, so a repository that is already past next is left
+// alone.
+func raiseRepositoryBuildNumber(ctx context.Context, client *Client, workspace, repoSlug string, next int) error {
+	current, err := getRepositoryBuildNumber(ctx, client, workspace, repoSlug)
+	if err != nil {
+		return err
+	}
+	if current >= next {
+		log.Printf("[DEBUG] Next build number of %s/%s is already %d", workspace, repoSlug, current)
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"type": "pipeline_build_number",
+		"next": next,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.PutContext(ctx, repositoryPipelineConfigEndpoint(workspace, repoSlug)+"/build_number", bytes.NewBuffer(payload))
+	return err
+}
+
+func resourceRepositoryPipelineConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+
+	if diags := applyRepositoryPipelineConfig(ctx, d, m, workspace, repoSlug, true); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", workspace, repoSlug))
+
+	return resourceRepositoryPipelineConfigRead(ctx, d, m)
+}
+
+func resourceRepositoryPipelineConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	workspace, repoSlug, err := repositoryId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if diags := applyRepositoryPipelineConfig(ctx, d, m, workspace, repoSlug, false); diags.HasError() {
+		return diags
+	}
+
+	return resourceRepositoryPipelineConfigRead(ctx, d, m)
+}
+
+// applyRepositoryPipelineConfig writes the configured settings. On create
+// every setting present in the configuration is written, on update only the
+// changed ones.
+func applyRepositoryPipelineConfig(ctx context.Context, d *schema.ResourceData, m interface{}, workspace, repoSlug string, create bool) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	set := func(key string) (interface{}, bool) {
+		// nolint:staticcheck
+		v, ok := d.GetOkExists(key)
+		if !ok || (!create && !d.HasChange(key)) {
+			return nil, false
+		}
+		return v, true
+	}
+
+	if enabled, ok := set("enabled"); ok {
+		config, err := getRepositoryPipelineConfig(ctx, &client, workspace, repoSlug)
+		if err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+		delete(config, "repository")
+		config["enabled"] = enabled
+
+		payload, err := json.Marshal(config)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		log.Printf("[DEBUG] Repository Pipeline Config update: %s", payload)
+
+		if _, err := client.PutContext(ctx, repositoryPipelineConfigEndpoint(workspace, repoSlug), bytes.NewBuffer(payload)); err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+	}
+
+	if next, ok := set("next_build_number"); ok {
+		if err := raiseRepositoryBuildNumber(ctx, &client, workspace, repoSlug, next.(int)); err != nil {
+			return apiErrorDiagnostics(err, d)
+		}
+	}
+
+	return nil
+}
+
+func resourceRepositoryPipelineConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, err := repositoryId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	config, err := getRepositoryPipelineConfig(ctx, &client, workspace, repoSlug)
+	if IsNotFound(err) {
+		log.Printf("[WARN] Repository Pipeline Config (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	buildNumber, err := getRepositoryBuildNumber(ctx, &client, workspace, repoSlug)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	enabled, _ := config["enabled"].(bool)
+
+	d.Set("workspace", workspace)
+	d.Set("repo_slug", repoSlug)
+	d.Set("enabled", enabled)
+	d.Set("next_build_number", buildNumber)
+
+	return nil
+}
+
+func resourceRepositoryPipelineConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// The configuration belongs to the repository; destroying the resource
+	// leaves the settings as they are.
+	return nil
+}
@@ -0,0 +1,186 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketRepositoryPipelineConfig_basic(t *testing.T) {
+	resourceName := "bitbucket_repository_pipeline_config.test"
+
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepositoryPipelineConfigConfig(workspace, rName, true, 100),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "workspace", workspace),
+					resource.TestCheckResourceAttr(resourceName, "repo_slug", rName),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "true"),
+					resource.TestCheckResourceAttr(resourceName, "next_build_number", "100"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccBitbucketRepositoryPipelineConfigConfig(workspace, rName, false, 200),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+					resource.TestCheckResourceAttr(resourceName, "next_build_number", "200"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketRepositoryPipelineConfigConfig(workspace, rName string, enabled bool, buildNumber int) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+}
+
+resource "bitbucket_repository_pipeline_config" "test" {
+  workspace         = bitbucket_repository.test.owner
+  repo_slug         = bitbucket_repository.test.name
+  enabled           = %[3]t
+  next_build_number = %[4]d
+}
+`, workspace, rName, enabled, buildNumber)
+}
+
+func TestRepositoryPipelineConfigLifecycle(t *testing.T) {
+	config := map[string]interface{}{
+		"type":           "repository_pipelines_configuration",
+		"enabled":        false,
+		"repository":     map[string]interface{}{"full_name": "acme/platform"},
+		"custom_setting": "kept",
+	}
+	nextBuild := 5
+	configPuts, buildPuts := 0, 0
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/2.0/repositories/acme/platform/pipelines_config" && r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(config)
+		case r.URL.Path == "/2.0/repositories/acme/platform/pipelines_config" && r.Method == http.MethodPut:
+			configPuts++
+			config = map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&config)
+			if _, ok := config["repository"]; ok {
+				t.Error("read-only repository sent back")
+			}
+			json.NewEncoder(w).Encode(config)
+		case r.URL.Path == "/2.0/repositories/acme/platform/pipelines_config/build_number" && r.Method == http.MethodGet:
+			fmt.Fprintf(w, `{"type":"pipeline_build_number","next":%d}`, nextBuild)
+		case r.URL.Path == "/2.0/repositories/acme/platform/pipelines_config/build_number" && r.Method == http.MethodPut:
+			buildPuts++
+			var body struct{ Next int }
+			json.NewDecoder(r.Body).Decode(&body)
+			nextBuild = body.Next
+			fmt.Fprintf(w, `{"type":"pipeline_build_number","next":%d}`, nextBuild)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+	meta := Clients{httpClient: *client}
+	r := resourceRepositoryPipelineConfig()
+	ctx := context.Background()
+
+	diff := func(state *terraform.InstanceState, raw map[string]interface{}) *terraform.InstanceDiff {
+		t.Helper()
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return diff
+	}
+	raw := map[string]interface{}{
+		"workspace":         "acme",
+		"repo_slug":         "platform",
+		"enabled":           true,
+		"next_build_number": 100,
+	}
+
+	state, diags := r.Apply(ctx, nil, diff(nil, raw), meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if configPuts != 1 || buildPuts != 1 || nextBuild != 100 {
+		t.Errorf("config puts=%d build puts=%d next build=%d", configPuts, buildPuts, nextBuild)
+	}
+	if config["custom_setting"] != "kept" || config["enabled"] != true {
+		t.Errorf("unexpected configuration: %v", config)
+	}
+	if state.ID != "acme/platform" || state.Attributes["next_build_number"] != "100" || state.Attributes["enabled"] != "true" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+
+	// Pipelines running past the configured build number are not drift.
+	nextBuild = 104
+	state, diags = r.RefreshWithoutUpgrade(ctx, state, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if d := diff(state, raw); d != nil && !d.Empty() {
+		t.Errorf("unexpected diff after pipelines ran: %v", d)
+	}
+
+	// Settings changed outside Terraform are detected and put back.
+	config["enabled"] = false
+	state, _ = r.RefreshWithoutUpgrade(ctx, state, meta)
+	if state.Attributes["enabled"] != "false" {
+		t.Fatalf("drift not detected: %v", state.Attributes)
+	}
+	state, diags = r.Apply(ctx, state, diff(state, raw), meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if configPuts != 2 || buildPuts != 1 {
+		t.Errorf("config puts=%d build puts=%d", configPuts, buildPuts)
+	}
+	if config["enabled"] != true || config["custom_setting"] != "kept" {
+		t.Errorf("unexpected configuration: %v", config)
+	}
+	if state.Attributes["enabled"] != "true" || state.Attributes["next_build_number"] != "104" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+
+	// A build number the repository has already passed is not written.
+	nextBuild = 120
+	raw["enabled"] = false
+	raw["next_build_number"] = 110
+	state, _ = r.RefreshWithoutUpgrade(ctx, state, meta)
+	state, diags = r.Apply(ctx, state, diff(state, raw), meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if configPuts != 3 || buildPuts != 1 || nextBuild != 120 || config["enabled"] != false {
+		t.Errorf("config puts=%d build puts=%d next build=%d config=%v", configPuts, buildPuts, nextBuild, config)
+	}
+
+	// A higher one is.
+	raw["next_build_number"] = 150
+	state, diags = r.Apply(ctx, state, diff(state, raw), meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if configPuts != 3 || buildPuts != 2 || nextBuild != 150 || state.Attributes["next_build_number"] != "150" {
+		t.Errorf("config puts=%d build puts=%d next build=%d state=%v", configPuts, buildPuts, nextBuild, state.Attributes)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -88,9 +87,9 @@ func resourceRepositoryPipelineRunnerCreate(ctx context.Context, d *schema.Resou
 	}
 
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines-config/runners", workspace, repoSlug)
-	res, err := client.Post(url, bytes.NewBuffer(payload))
+	res, err := client.PostContext(ctx, url, bytes.NewBuffer(payload))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(res.Body)
@@ -120,21 +119,21 @@ func resourceRepositoryPipelineRunnerRead(ctx context.Context, d *schema.Resourc
 	}
 
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines-config/runners/%s", workspace, repoSlug, runnerUUID)
-	res, err := client.Get(url)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	if res.StatusCode == http.StatusNotFound {
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
 		log.Printf("[WARN] Repository Pipeline Runner (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	if err := handleClientError(res, err); err != nil {
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if err := handleClientError(res, err); err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
 	body, readerr := io.ReadAll(res.Body)
 	if readerr != nil {
 		return diag.FromErr(readerr)
@@ -165,9 +164,9 @@ func resourceRepositoryPipelineRunnerUpdate(ctx context.Context, d *schema.Resou
 	}
 
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines-config/runners/%s", workspace, repoSlug, runnerUUID)
-	res, err := client.Put(url, bytes.NewBuffer(payload))
+	res, err := client.PutContext(ctx, url, bytes.NewBuffer(payload))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourceRepositoryPipelineRunnerRead(ctx, d, m)
@@ -182,9 +181,9 @@ func resourceRepositoryPipelineRunnerDelete(ctx context.Context, d *schema.Resou
 	}
 
 	url := fmt.Sprintf("2.0/repositories/%s/%s/pipelines-config/runners/%s", workspace, repoSlug, runnerUUID)
-	res, err := client.Delete(url)
+	res, err := client.DeleteContext(ctx, url)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return nil
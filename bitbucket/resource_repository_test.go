@@ -1,11 +1,14 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -297,6 +300,28 @@ func TestAccBitbucketRepository_duplicate(t *testing.T) {
 	})
 }
 
+func TestAccBitbucketRepository_waitForReady(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_repository.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoWaitForReadyConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "wait_for_ready", "true"),
+					resource.TestCheckResourceAttrSet(resourceName, "uuid"),
+				),
+			},
+		},
+	})
+}
+
 func testAccBitbucketRepoInheritConfig(workspace, rName string, enable bool) string {
 	return fmt.Sprintf(`
 resource "bitbucket_repository" "test" {
@@ -316,6 +341,16 @@ resource "bitbucket_repository" "test" {
 `, workspace, rName)
 }
 
+func testAccBitbucketRepoWaitForReadyConfig(workspace, rName string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner          = %[1]q
+  name           = %[2]q
+  wait_for_ready = true
+}
+`, workspace, rName)
+}
+
 func testAccBitbucketRepoProjectConfig(workspace, rName string) string {
 	return fmt.Sprintf(`
 resource "bitbucket_project" "test" {
@@ -409,3 +444,56 @@ func testAccCheckBitbucketRepositoryExists(n string) resource.TestCheckFunc {
 		return nil
 	}
 }
+
+func TestWaitForRepositoryReady(t *testing.T) {
+	defer func(interval time.Duration) { repositoryReadyPollInterval = interval }(repositoryReadyPollInterval)
+	repositoryReadyPollInterval = time.Millisecond
+
+	polls := 0
+	client, _ := newMockServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2.0/repositories/acme/fork":
+			polls++
+			if polls < 2 {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"type":"error","error":{"message":"Repository not found"}}`))
+				return
+			}
+			w.Write([]byte(`{"slug":"fork","mainbranch":{"name":"main"}}`))
+		case "/2.0/repositories/acme/fork/refs/branches/main":
+			if polls < 4 {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"type":"error","error":{"message":"Branch not found"}}`))
+				return
+			}
+			w.Write([]byte(`{"name":"main","target":{"hash":"abc"}}`))
+		case "/2.0/repositories/acme/empty":
+			w.Write([]byte(`{"slug":"empty","mainbranch":{"name":"main"}}`))
+		case "/2.0/repositories/acme/empty/refs/branches/main":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type":"error","error":{"message":"Branch not found"}}`))
+		case "/2.0/repositories/acme/empty/refs/branches":
+			w.Write([]byte(`{"values":[]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+
+	if err := waitForRepositoryReady(context.Background(), client, "acme", "fork", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if polls != 4 {
+		t.Errorf("polled the fork %d times, want 4", polls)
+	}
+
+	if err := waitForRepositoryReady(context.Background(), client, "acme", "empty", false); err != nil {
+		t.Errorf("empty repository not ready: %v", err)
+	}
+
+	// A fork whose main branch never appears times out.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := waitForRepositoryReady(ctx, client, "acme", "empty", true); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
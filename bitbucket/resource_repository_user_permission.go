@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -81,14 +80,14 @@ func resourceRepositoryUserPermissionPut(ctx context.Context, d *schema.Resource
 	repoSlug := d.Get("repo_slug").(string)
 	userSlug := d.Get("user_id").(string)
 
-	permissionReq, err := client.Put(fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/users/%s",
+	permissionReq, err := client.PutContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/users/%s",
 		workspace,
 		repoSlug,
 		userSlug,
 	), bytes.NewBuffer(payload))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(permissionReq.Body)
@@ -120,13 +119,13 @@ func resourceRepositoryUserPermissionRead(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
-	permissionReq, err := client.Get(fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/users/%s",
+	permissionReq, err := client.GetContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/users/%s",
 		workspace,
 		repoSlug,
 		userSlug,
 	))
 
-	if permissionReq.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Repository User Permission (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -168,7 +167,7 @@ func resourceRepositoryUserPermissionDelete(ctx context.Context, d *schema.Resou
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Delete(fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/users/%s",
+	_, err = client.DeleteContext(ctx, fmt.Sprintf("2.0/repositories/%s/%s/permissions-config/users/%s",
 		workspace,
 		repoSlug,
 		userSlug,
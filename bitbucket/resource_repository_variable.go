@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -82,9 +81,9 @@ func resourceRepositoryVariableCreate(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(err)
 	}
 
-	rvRes, res, err := pipeApi.CreateRepositoryPipelineVariable(c.AuthContext, rvcr, workspace, repoSlug)
+	rvRes, res, err := pipeApi.CreateRepositoryPipelineVariable(c.requestContext(ctx), rvcr, workspace, repoSlug)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.Set("uuid", rvRes.Uuid)
@@ -103,16 +102,16 @@ func resourceRepositoryVariableRead(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	rvRes, res, err := pipeApi.GetRepositoryPipelineVariable(c.AuthContext, workspace, repoSlug, d.Get("uuid").(string))
+	rvRes, res, err := pipeApi.GetRepositoryPipelineVariable(c.requestContext(ctx), workspace, repoSlug, d.Get("uuid").(string))
 
-	if res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Repository Variable (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.Set("uuid", rvRes.Uuid)
@@ -141,9 +140,9 @@ func resourceRepositoryVariableUpdate(ctx context.Context, d *schema.ResourceDat
 
 	rvcr := newRepositoryVariableFromResource(d)
 
-	_, res, err := pipeApi.UpdateRepositoryPipelineVariable(c.AuthContext, rvcr, workspace, repoSlug, d.Get("uuid").(string))
+	_, res, err := pipeApi.UpdateRepositoryPipelineVariable(c.requestContext(ctx), rvcr, workspace, repoSlug, d.Get("uuid").(string))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourceRepositoryVariableRead(ctx, d, m)
@@ -159,9 +158,9 @@ func resourceRepositoryVariableDelete(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(err)
 	}
 
-	res, err := pipeApi.DeleteRepositoryPipelineVariable(c.AuthContext, workspace, repoSlug, d.Get("uuid").(string))
+	res, err := pipeApi.DeleteRepositoryPipelineVariable(c.requestContext(ctx), workspace, repoSlug, d.Get("uuid").(string))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return nil
@@ -187,9 +187,9 @@ func resourceSnippetCreate(ctx context.Context, d *schema.ResourceData, m interf
 	}
 
 	endpoint := fmt.Sprintf("2.0/snippets/%s", workspace)
-	res, err := client.Post(endpoint, bytes.NewBuffer(jsonPayload))
+	res, err := client.PostContext(ctx, endpoint, bytes.NewBuffer(jsonPayload))
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	if res == nil {
@@ -235,7 +235,13 @@ func resourceSnippetRead(ctx context.Context, d *schema.ResourceData, m interfac
 	}
 
 	endpoint := fmt.Sprintf("2.0/snippets/%s/%s", workspace, encodedID)
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		log.Printf("[WARN] Snippet (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -244,12 +250,6 @@ func resourceSnippetRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.Errorf("no response returned from snippet call")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		log.Printf("[WARN] Snippet (%s) not found, removing from state", d.Id())
-		d.SetId("")
-		return nil
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading snippet: empty response body")
 	}
@@ -354,9 +354,9 @@ func resourceSnippetUpdate(ctx context.Context, d *schema.ResourceData, m interf
 	}
 
 	endpoint := fmt.Sprintf("2.0/snippets/%s/%s", workspace, encodedID)
-	res, err := client.Put(endpoint, bytes.NewBuffer(jsonPayload))
+	res, err := client.PutContext(ctx, endpoint, bytes.NewBuffer(jsonPayload))
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	if res == nil {
@@ -382,7 +382,7 @@ func resourceSnippetDelete(ctx context.Context, d *schema.ResourceData, m interf
 	}
 
 	endpoint := fmt.Sprintf("2.0/snippets/%s/%s", workspace, encodedID)
-	res, err := client.Delete(endpoint)
+	res, err := client.DeleteContext(ctx, endpoint)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -70,9 +69,9 @@ func resourceSshKeysCreate(ctx context.Context, d *schema.ResourceData, m interf
 	}
 
 	user := d.Get("user").(string)
-	sshKeyReq, res, err := sshApi.UsersSelectedUserSshKeysPost(c.AuthContext, user, sshKeyBody)
+	sshKeyReq, res, err := sshApi.UsersSelectedUserSshKeysPost(c.requestContext(ctx), user, sshKeyBody)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s", user, sshKeyReq.Uuid))
@@ -89,16 +88,16 @@ func resourceSshKeysRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.FromErr(err)
 	}
 
-	sshKeyReq, res, err := sshApi.UsersSelectedUserSshKeysKeyIdGet(c.AuthContext, keyId, user)
+	sshKeyReq, res, err := sshApi.UsersSelectedUserSshKeysKeyIdGet(c.requestContext(ctx), keyId, user)
 
-	if res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] SSH Key (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	if res.Body == nil {
@@ -129,9 +128,9 @@ func resourceSshKeysUpdate(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.FromErr(err)
 	}
 
-	_, res, err := sshApi.UsersSelectedUserSshKeysKeyIdPut(c.AuthContext, keyId, user, sshKeyBody)
+	_, res, err := sshApi.UsersSelectedUserSshKeysKeyIdPut(c.requestContext(ctx), keyId, user, sshKeyBody)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourceSshKeysRead(ctx, d, m)
@@ -146,9 +145,9 @@ func resourceSshKeysDelete(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.FromErr(err)
 	}
 
-	res, err := sshApi.UsersSelectedUserSshKeysKeyIdDelete(c.AuthContext, keyId, user)
+	res, err := sshApi.UsersSelectedUserSshKeysKeyIdDelete(c.requestContext(ctx), keyId, user)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return nil
@@ -115,9 +115,9 @@ func resourceUserGpgKeyCreate(ctx context.Context, d *schema.ResourceData, m int
 	}
 
 	endpoint := fmt.Sprintf("2.0/users/%s/gpg-keys", selectedUser)
-	res, err := client.Post(endpoint, bytes.NewBuffer(jsonPayload))
+	res, err := client.PostContext(ctx, endpoint, bytes.NewBuffer(jsonPayload))
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	if res == nil {
@@ -157,7 +157,13 @@ func resourceUserGpgKeyRead(ctx context.Context, d *schema.ResourceData, m inter
 	}
 
 	endpoint := fmt.Sprintf("2.0/users/%s/gpg-keys/%s", selectedUser, fingerprint)
-	res, err := client.Get(endpoint)
+	res, err := client.GetContext(ctx, endpoint)
+	if IsNotFound(err) {
+		log.Printf("[WARN] GPG key (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -166,12 +172,6 @@ func resourceUserGpgKeyRead(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.Errorf("no response returned from GPG key call")
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		log.Printf("[WARN] GPG key (%s) not found, removing from state", d.Id())
-		d.SetId("")
-		return nil
-	}
-
 	if res.Body == nil {
 		return diag.Errorf("error reading GPG key: empty response body")
 	}
@@ -232,7 +232,7 @@ func resourceUserGpgKeyDelete(ctx context.Context, d *schema.ResourceData, m int
 	}
 
 	endpoint := fmt.Sprintf("2.0/users/%s/gpg-keys/%s", selectedUser, fingerprint)
-	res, err := client.Delete(endpoint)
+	res, err := client.DeleteContext(ctx, endpoint)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"net/url"
 	"strings"
 
@@ -124,12 +123,12 @@ func resourceWorkspaceHookCreate(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	hookReq, err := client.Post(fmt.Sprintf("2.0/workspaces/%s/hooks",
+	hookReq, err := client.PostContext(ctx, fmt.Sprintf("2.0/workspaces/%s/hooks",
 		d.Get("workspace").(string),
 	), bytes.NewBuffer(payload))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(hookReq.Body)
@@ -149,12 +148,12 @@ func resourceWorkspaceHookCreate(ctx context.Context, d *schema.ResourceData, m
 func resourceWorkspaceHookRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(Clients).httpClient
 
-	hookReq, err := client.Get(fmt.Sprintf("2.0/workspaces/%s/hooks/%s",
+	hookReq, err := client.GetContext(ctx, fmt.Sprintf("2.0/workspaces/%s/hooks/%s",
 		d.Get("workspace").(string),
 		url.PathEscape(d.Id()),
 	))
 
-	if hookReq.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Repository Hook (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -201,13 +200,13 @@ func resourceWorkspaceHookUpdate(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Put(fmt.Sprintf("2.0/workspaces/%s/hooks/%s",
+	_, err = client.PutContext(ctx, fmt.Sprintf("2.0/workspaces/%s/hooks/%s",
 		d.Get("workspace").(string),
 		url.PathEscape(d.Id()),
 	), bytes.NewBuffer(payload))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourceWorkspaceHookRead(ctx, d, m)
@@ -215,7 +214,7 @@ func resourceWorkspaceHookUpdate(ctx context.Context, d *schema.ResourceData, m
 
 func resourceWorkspaceHookDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(Clients).httpClient
-	_, err := client.Delete(fmt.Sprintf("2.0/workspaces/%s/hooks/%s",
+	_, err := client.DeleteContext(ctx, fmt.Sprintf("2.0/workspaces/%s/hooks/%s",
 		d.Get("workspace").(string),
 		url.PathEscape(d.Id()),
 	))
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -100,9 +99,9 @@ func resourceWorkspacePipelineRunnerCreate(ctx context.Context, d *schema.Resour
 	}
 
 	url := fmt.Sprintf("2.0/workspaces/%s/pipelines-config/runners", workspace)
-	res, err := client.Post(url, bytes.NewBuffer(payload))
+	res, err := client.PostContext(ctx, url, bytes.NewBuffer(payload))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	body, readerr := io.ReadAll(res.Body)
@@ -132,21 +131,21 @@ func resourceWorkspacePipelineRunnerRead(ctx context.Context, d *schema.Resource
 	}
 
 	url := fmt.Sprintf("2.0/workspaces/%s/pipelines-config/runners/%s", workspace, runnerUUID)
-	res, err := client.Get(url)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	if res.StatusCode == http.StatusNotFound {
+	res, err := client.GetContext(ctx, url)
+	if IsNotFound(err) {
 		log.Printf("[WARN] Workspace Pipeline Runner (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	if err := handleClientError(res, err); err != nil {
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if err := handleClientError(res, err); err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
 	body, readerr := io.ReadAll(res.Body)
 	if readerr != nil {
 		return diag.FromErr(readerr)
@@ -176,9 +175,9 @@ func resourceWorkspacePipelineRunnerUpdate(ctx context.Context, d *schema.Resour
 	}
 
 	url := fmt.Sprintf("2.0/workspaces/%s/pipelines-config/runners/%s", workspace, runnerUUID)
-	res, err := client.Put(url, bytes.NewBuffer(payload))
+	res, err := client.PutContext(ctx, url, bytes.NewBuffer(payload))
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourceWorkspacePipelineRunnerRead(ctx, d, m)
@@ -193,9 +192,9 @@ func resourceWorkspacePipelineRunnerDelete(ctx context.Context, d *schema.Resour
 	}
 
 	url := fmt.Sprintf("2.0/workspaces/%s/pipelines-config/runners/%s", workspace, runnerUUID)
-	res, err := client.Delete(url)
+	res, err := client.DeleteContext(ctx, url)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return nil
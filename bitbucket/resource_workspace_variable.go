@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/DrFaust92/bitbucket-go-client"
@@ -72,12 +71,12 @@ func resourceWorkspaceVariableCreate(ctx context.Context, d *schema.ResourceData
 
 	log.Printf("[DEBUG] Workspace Variable Request: %#v", workspacePipeBody)
 
-	rvRes, res, err := pipeApi.CreatePipelineVariableForWorkspace(c.AuthContext, workspace, workspacePipeBody)
+	rvRes, res, err := pipeApi.CreatePipelineVariableForWorkspace(c.requestContext(ctx), workspace, workspacePipeBody)
 
 	log.Printf("[DEBUG] Workspace Variable Create Request Res: %#v", res)
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s", workspace, rvRes.Uuid))
@@ -94,18 +93,18 @@ func resourceWorkspaceVariableRead(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	rvRes, res, err := pipeApi.GetPipelineVariableForWorkspace(c.AuthContext, workspace, uuid)
+	rvRes, res, err := pipeApi.GetPipelineVariableForWorkspace(c.requestContext(ctx), workspace, uuid)
 
 	log.Printf("[DEBUG] Workspace Variable Get Request Res: %#v", res)
 
-	if res.StatusCode == http.StatusNotFound {
+	if IsNotFound(err) {
 		log.Printf("[WARN] Workspace Variable (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	d.Set("uuid", rvRes.Uuid)
@@ -133,9 +132,9 @@ func resourceWorkspaceVariableUpdate(ctx context.Context, d *schema.ResourceData
 
 	rvcr := newWorkspaceVariableFromResource(d)
 
-	_, res, err := pipeApi.UpdatePipelineVariableForWorkspace(c.AuthContext, rvcr, workspace, uuid)
+	_, res, err := pipeApi.UpdatePipelineVariableForWorkspace(c.requestContext(ctx), rvcr, workspace, uuid)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return resourceWorkspaceVariableRead(ctx, d, m)
@@ -150,9 +149,9 @@ func resourceWorkspaceVariableDelete(ctx context.Context, d *schema.ResourceData
 		return diag.FromErr(err)
 	}
 
-	res, err := pipeApi.DeletePipelineVariableForWorkspace(c.AuthContext, workspace, uuid)
+	res, err := pipeApi.DeletePipelineVariableForWorkspace(c.requestContext(ctx), workspace, uuid)
 	if err := handleClientError(res, err); err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, d)
 	}
 
 	return nil
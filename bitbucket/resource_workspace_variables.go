@@ -0,0 +1,255 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceWorkspaceVariables() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceWorkspaceVariablesCreate,
+		ReadWithoutTimeout:   resourceWorkspaceVariablesRead,
+		UpdateWithoutTimeout: resourceWorkspaceVariablesUpdate,
+		DeleteWithoutTimeout: resourceWorkspaceVariablesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				// Nothing is managed yet; the declared variables are adopted
+				// on the next apply.
+				d.Set("workspace", d.Id())
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Workspace slug or UUID",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"variable": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"value": {
+							Type:      schema.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+						"secured": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
+			},
+			"exclusive": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether workspace variables that are not declared are deleted",
+			},
+			"uuids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "UUIDs of the variables by key",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func workspaceVariablesEndpoint(workspace string) string {
+	return fmt.Sprintf("2.0/workspaces/%s/pipelines-config/variables", workspace)
+}
+
+func getWorkspaceVariables(ctx context.Context, client *Client, workspace string) (map[string]WorkspacePipelineVariable, error) {
+	values, err := client.GetPaginatedContext(ctx, workspaceVariablesEndpoint(workspace)+"?pagelen=100")
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make(map[string]WorkspacePipelineVariable, len(values))
+	for _, raw := range values {
+		var v WorkspacePipelineVariable
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+		variables[v.Key] = v
+	}
+	return variables, nil
+}
+
+// workspaceVariablesByKey indexes the elements of the `variable` set.
+func workspaceVariablesByKey(set interface{}) map[string]WorkspacePipelineVariable {
+	byKey := map[string]WorkspacePipelineVariable{}
+	for _, raw := range set.(*schema.Set).List() {
+		v := raw.(map[string]interface{})
+		byKey[v["key"].(string)] = WorkspacePipelineVariable{
+			Key:     v["key"].(string),
+			Value:   v["value"].(string),
+			Secured: v["secured"].(bool),
+		}
+	}
+	return byKey
+}
+
+func resourceWorkspaceVariablesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId(d.Get("workspace").(string))
+
+	if diags := syncWorkspaceVariables(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	return resourceWorkspaceVariablesRead(ctx, d, m)
+}
+
+func resourceWorkspaceVariablesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := syncWorkspaceVariables(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	return resourceWorkspaceVariablesRead(ctx, d, m)
+}
+
+// syncWorkspaceVariables writes the declared variables that are missing or
+// changed and deletes the ones no longer declared. With `exclusive`, every
+// other variable of the workspace is deleted as well.
+func syncWorkspaceVariables(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+	workspace := d.Id()
+	endpoint := workspaceVariablesEndpoint(workspace)
+
+	remote, err := getWorkspaceVariables(ctx, &client, workspace)
+	if err != nil {
+		return apiErrorDiagnostics(err, d)
+	}
+
+	o, n := d.GetChange("variable")
+	old, desired := workspaceVariablesByKey(o), workspaceVariablesByKey(n)
+	exclusive := d.Get("exclusive").(bool)
+
+	for key, v := range remote {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		if _, managed := old[key]; !managed && !exclusive {
+			continue
+		}
+		log.Printf("[DEBUG] Deleting variable %s of workspace %s", key, workspace)
+		if _, err := client.DeleteContext(ctx, fmt.Sprintf("%s/%s", endpoint, v.UUID)); err != nil && !IsNotFound(err) {
+			return diag.Errorf("error deleting variable %s: %s", key, err)
+		}
+	}
+
+	keys := make([]string, 0, len(desired))
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v := desired[key]
+		current, exists := remote[key]
+		if prev, ok := old[key]; exists && ok && prev == v && current.Secured == v.Secured {
+			continue
+		}
+
+		payload, err := json.Marshal(v)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if exists {
+			log.Printf("[DEBUG] Updating variable %s of workspace %s", key, workspace)
+			_, err = client.PutContext(ctx, fmt.Sprintf("%s/%s", endpoint, current.UUID), bytes.NewBuffer(payload))
+		} else {
+			log.Printf("[DEBUG] Creating variable %s of workspace %s", key, workspace)
+			_, err = client.PostContext(ctx, endpoint, bytes.NewBuffer(payload))
+		}
+		if err != nil {
+			return diag.Errorf("error writing variable %s: %s", key, err)
+		}
+	}
+
+	return nil
+}
+
+func resourceWorkspaceVariablesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+	workspace := d.Id()
+
+	remote, err := getWorkspaceVariables(ctx, &client, workspace)
+	if IsNotFound(err) {
+		log.Printf("[WARN] Workspace Variables (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	managed := workspaceVariablesByKey(d.Get("variable"))
+	exclusive := d.Get("exclusive").(bool)
+
+	variables := make([]interface{}, 0, len(remote))
+	uuids := map[string]interface{}{}
+	for key, current := range remote {
+		prev, ok := managed[key]
+		if !ok && !exclusive {
+			continue
+		}
+
+		// Secured values are never returned, so the value last written is
+		// kept.
+		value := current.Value
+		if current.Secured {
+			value = prev.Value
+		}
+
+		variables = append(variables, map[string]interface{}{
+			"key":     key,
+			"value":   value,
+			"secured": current.Secured,
+		})
+		uuids[key] = current.UUID
+	}
+
+	d.Set("workspace", workspace)
+	d.Set("variable", variables)
+	d.Set("uuids", uuids)
+
+	return nil
+}
+
+func resourceWorkspaceVariablesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+	endpoint := workspaceVariablesEndpoint(d.Id())
+
+	for key, uuid := range d.Get("uuids").(map[string]interface{}) {
+		_, err := client.DeleteContext(ctx, fmt.Sprintf("%s/%s", endpoint, uuid))
+		if err != nil && !IsNotFound(err) {
+			return diag.Errorf("error deleting variable %s: %s", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketWorkspaceVariables_basic(t *testing.T) {
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	// Workspace variables are shared by every test of the workspace, so the
+	// keys are made unique.
+	key := strings.ToUpper(strings.ReplaceAll(acctest.RandomWithPrefix("tf_test"), "-", "_"))
+	resourceName := "bitbucket_workspace_variables.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketWorkspaceVariablesDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketWorkspaceVariablesConfig(workspace, key, "eu-west-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "workspace", workspace),
+					resource.TestCheckResourceAttr(resourceName, "variable.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "uuids.%", "2"),
+					resource.TestCheckResourceAttrSet(resourceName, "uuids."+key+"_REGION"),
+					resource.TestCheckResourceAttrSet(resourceName, "uuids."+key+"_TOKEN"),
+				),
+			},
+			{
+				Config: testAccBitbucketWorkspaceVariablesConfig(workspace, key, "us-east-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "variable.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "uuids.%", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckBitbucketWorkspaceVariablesDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(Clients).genClient
+	pipeApi := client.ApiClient.PipelinesApi
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "bitbucket_workspace_variables" {
+			continue
+		}
+
+		for attr, uuid := range rs.Primary.Attributes {
+			if !strings.HasPrefix(attr, "uuids.") || attr == "uuids.%" {
+				continue
+			}
+
+			_, res, err := pipeApi.GetPipelineVariableForWorkspace(client.AuthContext, rs.Primary.ID, uuid)
+
+			if err == nil {
+				return fmt.Errorf("The resource was found should have errored")
+			}
+
+			if res.StatusCode != http.StatusNotFound {
+				return fmt.Errorf("Workspace Variable %s still exists", strings.TrimPrefix(attr, "uuids."))
+			}
+		}
+	}
+	return nil
+}
+
+func testAccBitbucketWorkspaceVariablesConfig(workspace, key, region string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_workspace_variables" "test" {
+  workspace = %[1]q
+
+  variable {
+    key   = "%[2]s_REGION"
+    value = %[3]q
+  }
+
+  variable {
+    key     = "%[2]s_TOKEN"
+    value   = "s3cr3t"
+    secured = true
+  }
+}
+`, workspace, key, region)
+}
+
+// fakeWorkspaceVariables is an in-memory workspace variables endpoint.
+type fakeWorkspaceVariables struct {
+	t       *testing.T
+	vars    map[string]DeploymentVariable
+	next    int
+	written []string
+	deleted []string
+}
+
+func (f *fakeWorkspaceVariables) handler(w http.ResponseWriter, r *http.Request) {
+	const endpoint = "/2.0/workspaces/acme/pipelines-config/variables"
+	uuid := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, endpoint), "/")
+	byUUID := func() (string, bool) {
+		for key, v := range f.vars {
+			if v.UUID == uuid {
+				return key, true
+			}
+		}
+		return "", false
+	}
+
+	switch {
+	case !strings.HasPrefix(r.URL.Path, endpoint):
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	case r.Method == http.MethodGet:
+		var values []DeploymentVariable
+		for _, v := range f.vars {
+			if v.Secured {
+				v.Value = ""
+			}
+			values = append(values, v)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"values": values})
+	case r.Method == http.MethodPost || r.Method == http.MethodPut:
+		var v DeploymentVariable
+		json.NewDecoder(r.Body).Decode(&v)
+		if r.Method == http.MethodPost {
+			f.next++
+			v.UUID = fmt.Sprintf("{v%d}", f.next)
+		} else if _, ok := byUUID(); !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else {
+			v.UUID = uuid
+		}
+		f.vars[v.Key] = v
+		f.written = append(f.written, r.Method+" "+v.Key+"="+v.Value)
+		json.NewEncoder(w).Encode(v)
+	case r.Method == http.MethodDelete:
+		key, ok := byUUID()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.vars, key)
+		f.deleted = append(f.deleted, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestWorkspaceVariablesLifecycle(t *testing.T) {
+	server := &fakeWorkspaceVariables{t: t, vars: map[string]DeploymentVariable{
+		"UNMANAGED": {UUID: "{u1}", Key: "UNMANAGED", Value: "keep"},
+		"REGION":    {UUID: "{r1}", Key: "REGION", Value: "us-east-1"},
+	}}
+	client, _ := newMockServerClient(t, server.handler)
+	meta := Clients{httpClient: *client}
+	r := resourceWorkspaceVariables()
+	ctx := context.Background()
+
+	apply := func(state *terraform.InstanceState, raw map[string]interface{}) *terraform.InstanceState {
+		t.Helper()
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff == nil || diff.Empty() {
+			return state
+		}
+		state, diags := r.Apply(ctx, state, diff, meta)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		return state
+	}
+	refresh := func(state *terraform.InstanceState) *terraform.InstanceState {
+		t.Helper()
+		state, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		return state
+	}
+	config := map[string]interface{}{
+		"workspace": "acme",
+		"variable": []interface{}{
+			map[string]interface{}{"key": "REGION", "value": "eu-west-1"},
+			map[string]interface{}{"key": "TOKEN", "value": "s3cr3t", "secured": true},
+		},
+	}
+
+	// Existing variables with a declared key are adopted, others are left
+	// alone.
+	state := apply(nil, config)
+	if !reflect.DeepEqual(server.written, []string{"PUT REGION=eu-west-1", "POST TOKEN=s3cr3t"}) || server.deleted != nil {
+		t.Fatalf("written %v, deleted %v", server.written, server.deleted)
+	}
+	if state.Attributes["variable.#"] != "2" || state.Attributes["uuids.REGION"] != "{r1}" || state.Attributes["uuids.%"] != "2" {
+		t.Errorf("unexpected state: %v", state.Attributes)
+	}
+
+	server.written = nil
+	state = apply(refresh(state), config)
+	if server.written != nil {
+		t.Errorf("unexpected writes: %v", server.written)
+	}
+
+	// Outside changes to unsecured values are reverted.
+	server.vars["REGION"] = DeploymentVariable{UUID: "{r1}", Key: "REGION", Value: "ap-south-1"}
+	state = apply(refresh(state), config)
+	if !reflect.DeepEqual(server.written, []string{"PUT REGION=eu-west-1"}) {
+		t.Errorf("written %v", server.written)
+	}
+
+	// With exclusive, undeclared variables are deleted.
+	config["exclusive"] = true
+	state = apply(refresh(state), config)
+	if !reflect.DeepEqual(server.deleted, []string{"UNMANAGED"}) || len(server.vars) != 2 {
+		t.Errorf("deleted %v, variables %v", server.deleted, server.vars)
+	}
+
+	// Removing a declaration deletes the variable.
+	config["variable"] = config["variable"].([]interface{})[:1]
+	state = apply(refresh(state), config)
+	if _, ok := server.vars["TOKEN"]; ok || state.Attributes["variable.#"] != "1" {
+		t.Errorf("variables %v, state %v", server.vars, state.Attributes)
+	}
+}
@@ -0,0 +1,175 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultMaxRetries is the number of times a failed request is retried
+	// when the provider does not configure `max_retries`.
+	defaultMaxRetries = 3
+	// defaultRetryMinWait is the base delay used for exponential backoff
+	// between retries when the API does not return a Retry-After header.
+	defaultRetryMinWait = time.Second
+	// defaultRetryMaxWait caps the exponential backoff delay.
+	defaultRetryMaxWait = 30 * time.Second
+)
+
+// RetryPolicy controls how requests that fail with a transient error are
+// retried. Rate limited requests (HTTP 429) are retried for every method, as
+// Bitbucket has not processed them. Transport errors and 5xx responses are only
+// retried for idempotent methods, so a POST is never replayed after the API
+// may already have acted on it.
+type RetryPolicy struct {
+	MaxRetries int
+	MinWait    time.Duration
+	MaxWait    time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used when the provider is configured
+// without any retry arguments.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: defaultMaxRetries,
+		MinWait:    defaultRetryMinWait,
+		MaxWait:    defaultRetryMaxWait,
+	}
+}
+
+// shouldRetry reports whether a request that produced resp or err is worth
+// sending again.
+func (p RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return isIdempotent(req.Method)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		return isIdempotent(req.Method)
+	}
+
+	return false
+}
+
+// backoff returns how long to wait before the given retry attempt (starting at
+// zero). It honours the Retry-After header when present, otherwise it uses
+// exponential backoff between MinWait and MaxWait with jitter, so that
+// parallel resources do not retry in lock step.
+func (p RetryPolicy) backoff(resp *http.Response, attempt int) time.Duration {
+	if wait, ok := retryAfter(resp); ok {
+		return wait
+	}
+
+	wait := p.MinWait * time.Duration(1<<uint(attempt))
+	if wait <= 0 || wait > p.MaxWait {
+		wait = p.MaxWait
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(wait-half)+1))
+}
+
+// retryAfter returns the delay requested by the Retry-After response header
+// (in seconds), if any.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+	}
+	return 0, false
+}
+
+// isIdempotent reports whether the HTTP method can safely be replayed.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryTransport is an http.RoundTripper that retries requests according to a
+// RetryPolicy. It is installed on the HTTP client shared by Client.Do and the
+// generated bitbucket.APIClient, so both paths behave the same way.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+func newRetryTransport(next http.RoundTripper, policy RetryPolicy) *retryTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &retryTransport{next: next, policy: policy}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+
+		if attempt >= t.policy.MaxRetries || !t.policy.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			// The body has been consumed and cannot be replayed.
+			return resp, err
+		}
+
+		wait := t.policy.backoff(resp, attempt)
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = fmt.Sprintf("HTTP %d", resp.StatusCode)
+			resp.Body.Close()
+		}
+		log.Printf("[DEBUG] Retrying %s %s in %s after %s (attempt %d/%d)", req.Method, req.URL, wait, reason, attempt+1, t.policy.MaxRetries)
+
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleepContext pauses for d, returning early with the context's error if ctx
+// is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
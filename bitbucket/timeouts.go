@@ -0,0 +1,65 @@
+package bitbucket
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// defaultResourceTimeout is how long an operation may run unless the
+// resource declares its own default or the configuration overrides it in a
+// `timeouts` block. It matches the default of the plugin SDK.
+const defaultResourceTimeout = 20 * time.Minute
+
+// addTimeouts declares a timeout for every operation a resource implements,
+// so that all resources accept a `timeouts` block, and binds the
+// WithoutTimeout operations to their timeout.
+//
+// The SDK only applies timeouts to the *Context functions. The WithoutTimeout
+// functions are wrapped here so their context, and with it every request,
+// retry backoff and polling loop of the operation, ends when the timeout
+// expires.
+func addTimeouts(resources map[string]*schema.Resource) {
+	for _, r := range resources {
+		if r.Timeouts == nil {
+			r.Timeouts = &schema.ResourceTimeout{}
+		}
+		t := r.Timeouts
+
+		if t.Create == nil {
+			t.Create = schema.DefaultTimeout(defaultResourceTimeout)
+		}
+		if t.Read == nil {
+			t.Read = schema.DefaultTimeout(defaultResourceTimeout)
+		}
+		if t.Delete == nil {
+			t.Delete = schema.DefaultTimeout(defaultResourceTimeout)
+		}
+		if t.Update == nil && (r.Update != nil || r.UpdateContext != nil || r.UpdateWithoutTimeout != nil) {
+			t.Update = schema.DefaultTimeout(defaultResourceTimeout)
+		}
+
+		if r.CreateWithoutTimeout != nil {
+			r.CreateWithoutTimeout = withOperationTimeout(schema.TimeoutCreate, r.CreateWithoutTimeout)
+		}
+		if r.ReadWithoutTimeout != nil {
+			r.ReadWithoutTimeout = withOperationTimeout(schema.TimeoutRead, r.ReadWithoutTimeout)
+		}
+		if r.UpdateWithoutTimeout != nil {
+			r.UpdateWithoutTimeout = withOperationTimeout(schema.TimeoutUpdate, r.UpdateWithoutTimeout)
+		}
+		if r.DeleteWithoutTimeout != nil {
+			r.DeleteWithoutTimeout = withOperationTimeout(schema.TimeoutDelete, r.DeleteWithoutTimeout)
+		}
+	}
+}
+
+func withOperationTimeout(key string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		ctx, cancel := context.WithTimeout(ctx, d.Timeout(key))
+		defer cancel()
+		return f(ctx, d, m)
+	}
+}
@@ -0,0 +1,106 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketTimeouts_basic(t *testing.T) {
+	resourceName := "bitbucket_repository.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketTimeoutsConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "timeouts.create", "5m"),
+					resource.TestCheckResourceAttr(resourceName, "timeouts.delete", "2m"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketTimeoutsConfig(workspace, rName string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+
+  timeouts {
+    create = "5m"
+    delete = "2m"
+  }
+}
+`, workspace, rName)
+}
+
+func TestAllResourcesDeclareTimeouts(t *testing.T) {
+	for name, r := range Provider().ResourcesMap {
+		if r.Timeouts == nil || r.Timeouts.Create == nil || r.Timeouts.Read == nil || r.Timeouts.Delete == nil {
+			t.Errorf("%s does not declare create, read and delete timeouts", name)
+			continue
+		}
+		if updatable := r.UpdateContext != nil || r.UpdateWithoutTimeout != nil || r.Update != nil; updatable != (r.Timeouts.Update != nil) {
+			t.Errorf("%s: update timeout declared = %t, update implemented = %t", name, r.Timeouts.Update != nil, updatable)
+		}
+	}
+}
+
+func TestAddTimeoutsBindsOperationContext(t *testing.T) {
+	var remaining time.Duration
+	r := &schema.Resource{
+		CreateWithoutTimeout: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatal("create context has no deadline")
+			}
+			remaining = time.Until(deadline)
+			d.SetId("id")
+			return nil
+		},
+		ReadWithoutTimeout:   func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics { return nil },
+		DeleteWithoutTimeout: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics { return nil },
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true, ForceNew: true},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Hour),
+		},
+	}
+	addTimeouts(map[string]*schema.Resource{"test": r})
+
+	if *r.Timeouts.Create != time.Hour || *r.Timeouts.Read != defaultResourceTimeout || r.Timeouts.Update != nil {
+		t.Errorf("unexpected timeouts: create=%v read=%v update=%v", *r.Timeouts.Create, *r.Timeouts.Read, r.Timeouts.Update)
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":     "x",
+		"timeouts": map[string]interface{}{"create": "90s"},
+	})
+	diff, err := r.Diff(context.Background(), nil, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, diags := r.Apply(context.Background(), nil, diff, nil); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if remaining <= 0 || remaining > 90*time.Second {
+		t.Errorf("create ran with %v left, want at most the configured 90s", remaining)
+	}
+}